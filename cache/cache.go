@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache stores opaque byte values under string keys.
+type Cache interface {
+	// Get returns the value and true on a hit, or false on a miss.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// DeletePrefix removes every key that starts with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+var (
+	// Store is the application-wide cache; it is nil when caching is disabled.
+	Store Cache
+	// TTL is how long entries live unless invalidated earlier.
+	TTL = time.Minute
+)
+
+// Setup configures Store from the environment:
+//
+//	CACHE_DRIVER   memory (default) | redis | none
+//	CACHE_TTL      entry lifetime, e.g. "60s"
+//	CACHE_SIZE     max entries for the memory driver
+//	REDIS_ADDR, REDIS_PASSWORD, REDIS_DB for the redis driver
+func Setup() error {
+	if value := os.Getenv("CACHE_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid CACHE_TTL: %w", err)
+		}
+		TTL = ttl
+	}
+
+	switch driver := os.Getenv("CACHE_DRIVER"); driver {
+	case "", "memory":
+		size := 1000
+		if value := os.Getenv("CACHE_SIZE"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 1 {
+				return fmt.Errorf("invalid CACHE_SIZE: %s", value)
+			}
+			size = parsed
+		}
+		Store = NewLRU(size)
+	case "redis":
+		db, _ := strconv.Atoi(os.Getenv("REDIS_DB"))
+		client := redis.NewClient(&redis.Options{
+			Addr:     os.Getenv("REDIS_ADDR"),
+			Password: os.Getenv("REDIS_PASSWORD"),
+			DB:       db,
+		})
+		if err := client.Ping(context.Background()).Err(); err != nil {
+			return fmt.Errorf("could not connect to redis: %w", err)
+		}
+		Store = NewRedis(client, "blog:")
+	case "none":
+		Store = nil
+	default:
+		return fmt.Errorf("unsupported CACHE_DRIVER: %s", driver)
+	}
+
+	return nil
+}
+
+// Remember returns the cached value for key, or calls load, caches its result
+// for TTL and returns it. Cache errors are logged and treated as misses so a
+// broken cache never takes the read path down.
+func Remember(ctx context.Context, key string, load func() ([]byte, error)) ([]byte, error) {
+	if Store == nil {
+		return load()
+	}
+
+	value, hit, err := Store.Get(ctx, key)
+	if err != nil {
+		log.Printf("Cache get %s failed: %v", key, err)
+	}
+	if hit {
+		return value, nil
+	}
+
+	value, err = load()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Store.Set(ctx, key, value, TTL); err != nil {
+		log.Printf("Cache set %s failed: %v", key, err)
+	}
+	return value, nil
+}
+
+// invalidateTimeout bounds how long a write waits for the cache to drop
+// stale entries.
+const invalidateTimeout = 5 * time.Second
+
+// Invalidate drops every entry under prefix. It does not take the request's
+// context: a client that disconnects right after a write must not leave stale
+// entries behind.
+func Invalidate(prefix string) {
+	if Store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), invalidateTimeout)
+	defer cancel()
+
+	if err := Store.DeletePrefix(ctx, prefix); err != nil {
+		log.Printf("Cache invalidation of %s failed: %v", prefix, err)
+	}
+}
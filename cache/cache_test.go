@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// stores runs each test against both drivers: the in-process LRU with a fake
+// clock, and Redis backed by miniredis.
+func stores(t *testing.T) map[string]struct {
+	store   Cache
+	advance func(time.Duration)
+} {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	lru := NewLRU(100)
+	lru.now = func() time.Time { return now }
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return map[string]struct {
+		store   Cache
+		advance func(time.Duration)
+	}{
+		"lru":   {lru, func(d time.Duration) { now = now.Add(d) }},
+		"redis": {NewRedis(client, "test:"), server.FastForward},
+	}
+}
+
+func TestHitAndMiss(t *testing.T) {
+	ctx := context.Background()
+	for name, tc := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			if _, hit, err := tc.store.Get(ctx, "posts:1"); err != nil || hit {
+				t.Fatalf("Get on empty cache = hit %v, err %v; want miss", hit, err)
+			}
+
+			if err := tc.store.Set(ctx, "posts:1", []byte("one"), time.Minute); err != nil {
+				t.Fatal(err)
+			}
+			value, hit, err := tc.store.Get(ctx, "posts:1")
+			if err != nil || !hit || string(value) != "one" {
+				t.Fatalf("Get = %q, hit %v, err %v; want \"one\"", value, hit, err)
+			}
+		})
+	}
+}
+
+func TestTTLExpiry(t *testing.T) {
+	ctx := context.Background()
+	for name, tc := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := tc.store.Set(ctx, "posts:1", []byte("one"), time.Minute); err != nil {
+				t.Fatal(err)
+			}
+
+			tc.advance(59 * time.Second)
+			if _, hit, _ := tc.store.Get(ctx, "posts:1"); !hit {
+				t.Fatal("entry expired before its TTL")
+			}
+
+			tc.advance(2 * time.Second)
+			if _, hit, _ := tc.store.Get(ctx, "posts:1"); hit {
+				t.Fatal("entry still served after its TTL")
+			}
+		})
+	}
+}
+
+func TestDeletePrefix(t *testing.T) {
+	ctx := context.Background()
+	for name, tc := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			for _, key := range []string{"posts:list:page=1", "posts:pinned", "posts*:odd", "trending:day"} {
+				if err := tc.store.Set(ctx, key, []byte(key), time.Minute); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := tc.store.DeletePrefix(ctx, "posts:"); err != nil {
+				t.Fatal(err)
+			}
+
+			for key, want := range map[string]bool{
+				"posts:list:page=1": false,
+				"posts:pinned":      false,
+				"posts*:odd":        true, // glob characters in keys are not patterns
+				"trending:day":      true,
+			} {
+				if _, hit, _ := tc.store.Get(ctx, key); hit != want {
+					t.Errorf("after DeletePrefix, %s hit = %v, want %v", key, hit, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	lru := NewLRU(2)
+	lru.Set(ctx, "a", []byte("a"), 0)
+	lru.Set(ctx, "b", []byte("b"), 0)
+	lru.Get(ctx, "a")
+	lru.Set(ctx, "c", []byte("c"), 0)
+
+	if _, hit, _ := lru.Get(ctx, "b"); hit {
+		t.Error("b should have been evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, hit, _ := lru.Get(ctx, key); !hit {
+			t.Errorf("%s should still be cached", key)
+		}
+	}
+}
+
+func useStore(t *testing.T, store Cache) {
+	previous := Store
+	Store = store
+	t.Cleanup(func() { Store = previous })
+}
+
+func TestRememberLoadsOnceUntilInvalidated(t *testing.T) {
+	for name, tc := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			useStore(t, tc.store)
+
+			loads := 0
+			load := func() ([]byte, error) {
+				loads++
+				return []byte("page"), nil
+			}
+
+			for i := 0; i < 3; i++ {
+				if value, err := Remember(context.Background(), "posts:list", load); err != nil || string(value) != "page" {
+					t.Fatalf("Remember = %q, %v", value, err)
+				}
+			}
+			if loads != 1 {
+				t.Fatalf("loaded %d times, want 1", loads)
+			}
+
+			Invalidate("posts:")
+			Remember(context.Background(), "posts:list", load)
+			if loads != 2 {
+				t.Fatalf("loaded %d times after invalidation, want 2", loads)
+			}
+		})
+	}
+}
+
+func TestInvalidateOutlivesCancelledRequest(t *testing.T) {
+	for name, tc := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			useStore(t, tc.store)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			tc.store.Set(ctx, "posts:list", []byte("stale"), time.Minute)
+			cancel()
+
+			Invalidate("posts:")
+
+			if _, hit, _ := tc.store.Get(context.Background(), "posts:list"); hit {
+				t.Fatal("entry survived invalidation")
+			}
+		})
+	}
+}
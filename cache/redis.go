@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis shares cached entries between replicas. It accepts any
+// redis.UniversalClient, so tests can point it at a local stand-in server.
+type Redis struct {
+	client    redis.UniversalClient
+	namespace string
+}
+
+func NewRedis(client redis.UniversalClient, namespace string) *Redis {
+	return &Redis{client: client, namespace: namespace}
+}
+
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, c.namespace+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, c.namespace+key, value, ttl).Err()
+}
+
+// DeletePrefix walks matching keys with SCAN rather than KEYS so it does not
+// block the server on large keyspaces.
+func (c *Redis) DeletePrefix(ctx context.Context, prefix string) error {
+	iter := c.client.Scan(ctx, 0, c.namespace+escapePattern(prefix)+"*", 500).Iterator()
+
+	var batch []string
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == 500 {
+			if err := c.client.Del(ctx, batch...).Err(); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	if len(batch) > 0 {
+		return c.client.Del(ctx, batch...).Err()
+	}
+	return nil
+}
+
+// escapePattern escapes glob metacharacters for use in a SCAN MATCH pattern.
+func escapePattern(s string) string {
+	escaped := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '*', '?', '[', ']', '\\':
+			escaped = append(escaped, '\\')
+		}
+		escaped = append(escaped, s[i])
+	}
+	return string(escaped)
+}
@@ -0,0 +1,15 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// CacheControl returns the Cache-Control header for a named route, which can
+// be overridden with CACHE_CONTROL_<NAME> (e.g. CACHE_CONTROL_POSTS).
+func CacheControl(name string, fallback string) string {
+	if value := os.Getenv("CACHE_CONTROL_" + strings.ToUpper(name)); value != "" {
+		return value
+	}
+	return fallback
+}
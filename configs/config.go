@@ -24,7 +24,7 @@ func ConnectDatabase() {
 	var err error
 
 	if dbType == "postgres" {
-		dsn = "host=" + host + " user=" + user + " password=" + password + " dbname=" + dbname + " port=" + port + " sslmode=disable TimeZone=Asia/Ho_Chi_Minh"
+		dsn = PostgresDSN()
 		DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	} else if dbType == "mysql" {
 		dsn = user + ":" + password + "@tcp(" + host + ":" + port + ")/" + dbname + "?charset=utf8mb4&parseTime=True&loc=Local"
@@ -39,3 +39,14 @@ func ConnectDatabase() {
 
 	log.Println("Database connection established")
 }
+
+// PostgresDSN builds the Postgres connection string from the DB_* environment variables.
+func PostgresDSN() string {
+	host := os.Getenv("DB_HOST")
+	user := os.Getenv("DB_USER")
+	password := os.Getenv("DB_PASSWORD")
+	dbname := os.Getenv("DB_NAME")
+	port := os.Getenv("DB_PORT")
+
+	return "host=" + host + " user=" + user + " password=" + password + " dbname=" + dbname + " port=" + port + " sslmode=disable TimeZone=Asia/Ho_Chi_Minh"
+}
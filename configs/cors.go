@@ -6,11 +6,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const AllowedOrigin = "http://localhost:3000"
+
 func SetupCORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "http://localhost:3000")
+		c.Header("Access-Control-Allow-Origin", AllowedOrigin)
 		c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization, If-Match, If-None-Match, If-Modified-Since")
+		c.Header("Access-Control-Expose-Headers", "ETag, Last-Modified")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusOK)
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordActivity queues a view or read of the post by the current visitor.
+// Bots are ignored.
+func recordActivity(c *gin.Context, kind string, postID uint) {
+	userAgent := c.Request.UserAgent()
+	if services.IsBot(userAgent) {
+		return
+	}
+
+	var userID *uint
+	if value, exists := c.Get("userID"); exists {
+		id := value.(uint)
+		userID = &id
+	}
+
+	services.RecordActivity(services.ActivityEvent{
+		Kind:      kind,
+		PostID:    postID,
+		VisitorID: services.VisitorID(userID, c.ClientIP(), userAgent),
+		UserID:    userID,
+		Referrer:  c.Request.Referer(),
+	})
+}
+
+// ReadPost is pinged by the client once the visitor has scrolled or stayed
+// far enough into the post to count as having read it.
+func ReadPost(c *gin.Context) {
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.Select("id").First(&post, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	recordActivity(c, services.ActivityRead, post.ID)
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Read recorded"})
+}
+
+// GetPostStats returns view and read totals for people who can edit or view
+// the post as collaborators.
+func GetPostStats(c *gin.Context) {
+	post, ok := findPostWithRole(c, models.CollaboratorViewer)
+	if !ok {
+		return
+	}
+
+	var uniqueVisitors int64
+	if err := config.DB.Model(&models.PostView{}).
+		Where("post_id = ?", post.ID).
+		Distinct("visitor_id").
+		Count(&uniqueVisitors).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load post stats"})
+		return
+	}
+
+	readRatio := 0.0
+	if post.Views > 0 {
+		readRatio = float64(post.Reads) / float64(post.Views)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"post_id":         post.ID,
+		"views":           post.Views,
+		"reads":           post.Reads,
+		"read_ratio":      readRatio,
+		"unique_visitors": uniqueVisitors,
+	}})
+}
@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/services"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// siteMetric is one of the counters on the admin dashboard; value is what
+// each row adds to it. Claps use the row's count because a single row can
+// hold several claps. since, when set, returns the first day the table has
+// rows for: earlier days are reported as null rather than zero.
+type siteMetric struct {
+	name  string
+	model interface{}
+	value string
+	since func() (time.Time, bool, error)
+}
+
+var siteMetrics = []siteMetric{
+	{"users", &models.User{}, "1", nil},
+	{"posts", &models.Post{}, "1", nil},
+	{"comments", &models.Comment{}, "1", nil},
+	{"claps", &models.Clap{}, "count", clapsLoggedSince},
+}
+
+// clapsLoggedSince returns the day of the first logged clap. Claps given
+// before they were logged one by one only exist in the post totals, so they
+// can't be placed on a day.
+func clapsLoggedSince() (time.Time, bool, error) {
+	var first models.Clap
+	if err := config.DB.Select("created_at").Order("created_at ASC").Limit(1).Find(&first).Error; err != nil {
+		return time.Time{}, false, err
+	}
+	if first.CreatedAt.IsZero() {
+		return time.Time{}, false, nil
+	}
+	return services.StatsDay(first.CreatedAt), true, nil
+}
+
+// GetAdminStats reports site-wide activity over ?from/?to (YYYY-MM-DD, default
+// the last 30 days): totals with growth against the previous period of the
+// same length, a daily series, the most active authors, the most used tags
+// and storage used by uploads. Days are UTC. Claps are only counted from the
+// first logged clap ("since"); earlier days of the series are null. Everything
+// is plain GROUP BY/SUM so it runs the same on Postgres and MySQL.
+func GetAdminStats(c *gin.Context) {
+	from, to, ok := statsRange(c)
+	if !ok {
+		return
+	}
+	end := to.AddDate(0, 0, 1)
+	previousFrom := from.Add(-end.Sub(from))
+
+	days := make(map[string]gin.H)
+	series := make([]gin.H, 0, int(end.Sub(from).Hours()/24))
+	for day := from; day.Before(end); day = day.AddDate(0, 0, 1) {
+		entry := gin.H{"date": day.Format("2006-01-02")}
+		for _, metric := range siteMetrics {
+			entry[metric.name] = int64(0)
+		}
+		days[day.Format("2006-01-02")] = entry
+		series = append(series, entry)
+	}
+
+	dayExpr := services.UTCDay("created_at")
+
+	totals := gin.H{}
+	for _, metric := range siteMetrics {
+		var since *time.Time
+		if metric.since != nil {
+			day, ok, err := metric.since()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stats"})
+				return
+			}
+			if ok {
+				since = &day
+			}
+		}
+
+		// All three figures in one pass over the table.
+		var sums struct {
+			Total    int64
+			Recent   int64
+			Previous int64
+		}
+		if err := config.DB.Model(metric.model).
+			Select("COALESCE(SUM("+metric.value+"), 0) AS total, "+
+				"COALESCE(SUM(CASE WHEN created_at >= ? THEN "+metric.value+" ELSE 0 END), 0) AS recent, "+
+				"COALESCE(SUM(CASE WHEN created_at >= ? AND created_at < ? THEN "+metric.value+" ELSE 0 END), 0) AS previous",
+				from, previousFrom, from).
+			Where("created_at < ?", end).
+			Scan(&sums).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stats"})
+			return
+		}
+
+		var growth *float64
+		if sums.Previous > 0 && (since == nil || !since.After(previousFrom)) {
+			value := float64(sums.Recent-sums.Previous) / float64(sums.Previous) * 100
+			growth = &value
+		}
+		total := gin.H{
+			"total":    sums.Total,
+			"new":      sums.Recent,
+			"previous": sums.Previous,
+			"growth":   growth,
+		}
+		if metric.since != nil {
+			total["since"] = nil
+			if since != nil {
+				total["since"] = since.Format("2006-01-02")
+			}
+		}
+		totals[metric.name] = total
+
+		var rows []struct {
+			Day   string
+			Total int64
+		}
+		if err := config.DB.Model(metric.model).
+			Select(dayExpr+" AS day, SUM("+metric.value+") AS total").
+			Where("created_at >= ? AND created_at < ?", from, end).
+			Group(dayExpr).
+			Scan(&rows).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stats"})
+			return
+		}
+		for _, row := range rows {
+			day, err := services.ParseStatsDay(row.Day)
+			if err != nil {
+				log.Println("Skipping stats row:", err)
+				continue
+			}
+			if entry := days[day.Format("2006-01-02")]; entry != nil {
+				entry[metric.name] = row.Total
+			}
+		}
+		if since != nil {
+			for day := from; day.Before(*since) && day.Before(end); day = day.AddDate(0, 0, 1) {
+				days[day.Format("2006-01-02")][metric.name] = nil
+			}
+		}
+	}
+
+	var authors []struct {
+		ID        uint   `json:"id"`
+		Name      string `json:"name"`
+		Username  string `json:"username"`
+		PostCount int64  `json:"posts"`
+		ClapCount int64  `json:"claps"`
+	}
+	if err := config.DB.Model(&models.Post{}).
+		Select("users.id, users.name, users.username, COUNT(posts.id) AS post_count, COALESCE(SUM(posts.claps), 0) AS clap_count").
+		Joins("JOIN users ON users.id = posts.user_id").
+		Where("posts.created_at >= ? AND posts.created_at < ?", from, end).
+		Group("users.id, users.name, users.username").
+		Order("post_count DESC, clap_count DESC").
+		Limit(statsTopLimit).
+		Scan(&authors).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stats"})
+		return
+	}
+
+	var tags []struct {
+		Name      string `json:"name"`
+		PostCount int64  `json:"posts"`
+	}
+	if err := config.DB.Table("post_tags").
+		Select("tags.name, COUNT(*) AS post_count").
+		Joins("JOIN tags ON tags.id = post_tags.tag_id").
+		Joins("JOIN posts ON posts.id = post_tags.post_id").
+		Where("posts.created_at >= ? AND posts.created_at < ?", from, end).
+		Group("tags.id, tags.name").
+		Order("post_count DESC, tags.name ASC").
+		Limit(statsTopLimit).
+		Scan(&tags).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stats"})
+		return
+	}
+
+	var storage, storageNew struct {
+		Files int64
+		Bytes int64
+	}
+	if err := config.DB.Model(&models.Upload{}).
+		Select("COUNT(*) AS files, COALESCE(SUM(size), 0) AS bytes").
+		Scan(&storage).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stats"})
+		return
+	}
+	if err := config.DB.Model(&models.Upload{}).
+		Select("COUNT(*) AS files, COALESCE(SUM(size), 0) AS bytes").
+		Where("created_at >= ? AND created_at < ?", from, end).
+		Scan(&storageNew).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"from":        from.Format("2006-01-02"),
+		"to":          to.Format("2006-01-02"),
+		"totals":      totals,
+		"series":      series,
+		"top_authors": authors,
+		"top_tags":    tags,
+		"storage": gin.H{
+			"files":     storage.Files,
+			"bytes":     storage.Bytes,
+			"new_files": storageNew.Files,
+			"new_bytes": storageNew.Bytes,
+		},
+	}})
+}
@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/responses"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetAuditLogs(c *gin.Context) {
+	query, ok := auditLogQuery(c)
+	if !ok {
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		exportAuditLogsCSV(c, query)
+		return
+	}
+
+	pageNum, perPageNum, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count audit logs"})
+		return
+	}
+
+	var logs []models.AuditLog
+	if err := query.
+		Order("created_at DESC, id DESC").
+		Limit(perPageNum).
+		Offset((pageNum - 1) * perPageNum).
+		Find(&logs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit logs"})
+		return
+	}
+
+	responses.PaginateResponse(c, logs, total, pageNum, perPageNum)
+}
+
+func auditLogQuery(c *gin.Context) (*gorm.DB, bool) {
+	query := config.DB.Model(&models.AuditLog{})
+
+	if actorID := c.Query("actor_id"); actorID != "" {
+		id, err := strconv.ParseUint(actorID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid actor_id"})
+			return nil, false
+		}
+		query = query.Where("actor_id = ?", id)
+	}
+
+	if action := c.Query("action"); action != "" {
+		query = query.Where("action = ?", action)
+	}
+
+	if targetType := c.Query("target_type"); targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+
+	if targetID := c.Query("target_id"); targetID != "" {
+		id, err := strconv.ParseUint(targetID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid target_id"})
+			return nil, false
+		}
+		query = query.Where("target_id = ?", id)
+	}
+
+	if from := c.Query("from"); from != "" {
+		t, err := parseDateParam(from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date"})
+			return nil, false
+		}
+		query = query.Where("created_at >= ?", t)
+	}
+
+	if to := c.Query("to"); to != "" {
+		// A plain date includes the whole of that day.
+		if day, err := time.ParseInLocation("2006-01-02", to, time.Local); err == nil {
+			query = query.Where("created_at < ?", day.AddDate(0, 0, 1))
+		} else if t, err := time.Parse(time.RFC3339, to); err == nil {
+			query = query.Where("created_at <= ?", t)
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date"})
+			return nil, false
+		}
+	}
+
+	return query, true
+}
+
+// parseDateParam accepts either RFC3339 timestamps or plain YYYY-MM-DD dates.
+func parseDateParam(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
+
+func exportAuditLogsCSV(c *gin.Context, query *gorm.DB) {
+	rows, err := query.Order("created_at DESC, id DESC").Rows()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit logs"})
+		return
+	}
+	defer rows.Close()
+
+	filename := fmt.Sprintf("audit-%s.csv", time.Now().Format("20060102-150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"id", "created_at", "actor_id", "action", "target_type", "target_id", "ip", "before", "after"})
+
+	for rows.Next() {
+		var entry models.AuditLog
+		if err := config.DB.ScanRows(rows, &entry); err != nil {
+			log.Println("Failed to scan audit log row:", err)
+			break
+		}
+
+		writer.Write([]string{
+			strconv.FormatUint(uint64(entry.ID), 10),
+			entry.CreatedAt.Format(time.RFC3339),
+			strconv.FormatUint(uint64(entry.ActorID), 10),
+			csvCell(entry.Action),
+			csvCell(entry.TargetType),
+			strconv.FormatUint(uint64(entry.TargetID), 10),
+			csvCell(entry.IP),
+			csvCell(entry.Before),
+			csvCell(entry.After),
+		})
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Println("Failed to write audit CSV:", err)
+	}
+}
+
+// csvCell stops spreadsheets from evaluating a cell as a formula by prefixing
+// values that start with a formula character with a quote.
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
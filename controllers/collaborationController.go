@@ -0,0 +1,376 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/services"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+	"gorm.io/gorm"
+)
+
+const (
+	collaborationMaxMessageBytes = 8 << 10
+	collaborationWriteTimeout    = 10 * time.Second
+)
+
+func toCollaboratorResponse(collaborator models.PostCollaborator) gin.H {
+	return gin.H{
+		"id":            collaborator.ID,
+		"post_id":       collaborator.PostID,
+		"user":          models.ToUserResponse(collaborator.User),
+		"role":          collaborator.Role,
+		"status":        collaborator.Status,
+		"invited_by_id": collaborator.InvitedByID,
+		"responded_at":  collaborator.RespondedAt,
+		"created_at":    collaborator.CreatedAt,
+	}
+}
+
+func GetCollaborators(c *gin.Context) {
+	post, ok := findPostWithRole(c, models.CollaboratorViewer)
+	if !ok {
+		return
+	}
+
+	var collaborators []models.PostCollaborator
+	if err := config.DB.Preload("User").Where("post_id = ?", post.ID).Order("id ASC").Find(&collaborators).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve collaborators"})
+		return
+	}
+
+	var response []gin.H
+	for _, collaborator := range collaborators {
+		response = append(response, toCollaboratorResponse(collaborator))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+// InviteCollaborator invites a user as a co-author. The invitation stays
+// pending until the invitee accepts it; inviting again after a decline sends
+// a fresh invitation.
+func InviteCollaborator(c *gin.Context) {
+	post, ok := findPostWithRole(c, models.CollaboratorOwner)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		UserID uint   `json:"user_id" binding:"required"`
+		Role   string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.Role == "" {
+		request.Role = models.CollaboratorEditor
+	}
+	if !services.ValidCollaboratorRole(request.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be one of owner, editor or viewer"})
+		return
+	}
+
+	if request.UserID == post.UserID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The author is already a collaborator"})
+		return
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, request.UserID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	inviterID := c.MustGet("userID").(uint)
+	collaborator := models.PostCollaborator{PostID: post.ID, UserID: user.ID}
+	err := config.DB.Where(&collaborator).First(&collaborator).Error
+	switch {
+	case err == nil && collaborator.Status == models.InvitationAccepted:
+		c.JSON(http.StatusConflict, gin.H{"error": "User is already a collaborator"})
+		return
+	case err == nil:
+		err = config.DB.Model(&collaborator).Updates(map[string]interface{}{
+			"role":          request.Role,
+			"status":        models.InvitationPending,
+			"invited_by_id": inviterID,
+			"responded_at":  nil,
+		}).Error
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		collaborator.Role = request.Role
+		collaborator.Status = models.InvitationPending
+		collaborator.InvitedByID = inviterID
+		err = config.DB.Create(&collaborator).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not invite collaborator"})
+		return
+	}
+
+	if err := services.Notify(models.Notification{
+		UserID:  user.ID,
+		ActorID: inviterID,
+		Type:    models.NotificationInvite,
+		PostID:  &post.ID,
+		Message: "invited you to collaborate as " + request.Role,
+	}); err != nil {
+		log.Println("Error creating invitation notification:", err)
+	}
+
+	collaborator.User = user
+	c.JSON(http.StatusOK, gin.H{"message": "Collaborator invited successfully", "data": toCollaboratorResponse(collaborator)})
+}
+
+func UpdateCollaboratorRole(c *gin.Context) {
+	post, ok := findPostWithRole(c, models.CollaboratorOwner)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil || !services.ValidCollaboratorRole(request.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be one of owner, editor or viewer"})
+		return
+	}
+
+	collaboratorID, ok := parseIDParam(c, "userId", "user")
+	if !ok {
+		return
+	}
+
+	var collaborator models.PostCollaborator
+	if err := config.DB.Preload("User").
+		Where("post_id = ? AND user_id = ?", post.ID, collaboratorID).
+		First(&collaborator).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collaborator not found"})
+		return
+	}
+
+	if err := config.DB.Model(&collaborator).Update("role", request.Role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update collaborator"})
+		return
+	}
+
+	invalidatePostCache()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collaborator updated successfully", "data": toCollaboratorResponse(collaborator)})
+}
+
+// RemoveCollaborator revokes access. Owners can remove anyone; any
+// collaborator can remove themselves to leave the post.
+func RemoveCollaborator(c *gin.Context) {
+	collaboratorID, ok := parseIDParam(c, "userId", "user")
+	if !ok {
+		return
+	}
+
+	minimum := models.CollaboratorOwner
+	if collaboratorID == c.MustGet("userID").(uint) {
+		minimum = models.CollaboratorViewer
+	}
+
+	post, ok := findPostWithRole(c, minimum)
+	if !ok {
+		return
+	}
+
+	if err := config.DB.Where("post_id = ? AND user_id = ?", post.ID, collaboratorID).
+		Delete(&models.PostCollaborator{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not remove collaborator"})
+		return
+	}
+
+	invalidatePostCache()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collaborator removed successfully"})
+}
+
+func GetMyInvitations(c *gin.Context) {
+	var invitations []models.PostCollaborator
+	if err := config.DB.Preload("Post").
+		Preload("InvitedBy").
+		Where("user_id = ? AND status = ?", c.MustGet("userID").(uint), models.InvitationPending).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve invitations"})
+		return
+	}
+
+	var response []gin.H
+	for _, invitation := range invitations {
+		response = append(response, gin.H{
+			"id":         invitation.ID,
+			"role":       invitation.Role,
+			"post":       gin.H{"id": invitation.Post.ID, "title": invitation.Post.Title},
+			"invited_by": models.ToUserResponse(invitation.InvitedBy),
+			"created_at": invitation.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+func AcceptInvitation(c *gin.Context) {
+	respondToInvitation(c, models.InvitationAccepted)
+}
+
+func DeclineInvitation(c *gin.Context) {
+	respondToInvitation(c, models.InvitationDeclined)
+}
+
+func respondToInvitation(c *gin.Context, status string) {
+	invitationID, ok := parseIDParam(c, "id", "invitation")
+	if !ok {
+		return
+	}
+
+	var invitation models.PostCollaborator
+	if err := config.DB.Where("id = ? AND user_id = ?", invitationID, c.MustGet("userID").(uint)).
+		First(&invitation).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+		return
+	}
+
+	if invitation.Status != models.InvitationPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Invitation has already been " + invitation.Status})
+		return
+	}
+
+	now := time.Now()
+	if err := config.DB.Model(&invitation).Updates(map[string]interface{}{
+		"status":       status,
+		"responded_at": now,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update invitation"})
+		return
+	}
+
+	if status == models.InvitationAccepted {
+		invalidatePostCache()
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation " + status, "data": invitation})
+}
+
+// CollaborationSocket upgrades to a WebSocket that shares presence, cursor,
+// selection and typing events between the people editing a post.
+//
+// Clients send {"type": "cursor" | "selection" | "typing", "data": {...}} and
+// receive the same shape with the sender in "user", plus "presence", "join"
+// and "leave" messages.
+func CollaborationSocket(c *gin.Context) {
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.First(&post, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	allowed, err := services.CanCollaborate(post, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check permissions"})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not a collaborator on this post"})
+		return
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	client := services.NewPresenceClient(services.PresenceUser{ID: user.ID, Name: user.Name, Photo: user.Photo})
+	server := websocket.Server{
+		Handshake: checkWebSocketOrigin,
+		Handler: func(ws *websocket.Conn) {
+			serveCollaboration(ws, post.ID, client)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+func serveCollaboration(ws *websocket.Conn, postID uint, client *services.PresenceClient) {
+	defer ws.Close()
+	ws.MaxPayloadBytes = collaborationMaxMessageBytes
+
+	services.Presence.Join(postID, client)
+	defer services.Presence.Leave(postID, client)
+
+	go func() {
+		for message := range client.Send {
+			ws.SetWriteDeadline(time.Now().Add(collaborationWriteTimeout))
+			if err := websocket.JSON.Send(ws, message); err != nil {
+				ws.Close()
+				return
+			}
+		}
+	}()
+
+	for {
+		var message struct {
+			Type string          `json:"type"`
+			Data json.RawMessage `json:"data"`
+		}
+		if err := websocket.JSON.Receive(ws, &message); err != nil {
+			return
+		}
+
+		switch message.Type {
+		case "cursor", "selection", "typing":
+			services.Presence.Broadcast(postID, client, services.PresenceMessage{Type: message.Type, Data: message.Data})
+		}
+	}
+}
+
+func checkWebSocketOrigin(cfg *websocket.Config, req *http.Request) error {
+	origin := req.Header.Get("Origin")
+	if origin != "" && origin != config.AllowedOrigin {
+		return errors.New("origin not allowed")
+	}
+	return nil
+}
+
+// findPostWithRole loads the post from the :id param and checks that the
+// current user holds at least the given role on it.
+func findPostWithRole(c *gin.Context, minimum string) (models.Post, bool) {
+	var post models.Post
+
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return post, false
+	}
+
+	if err := config.DB.First(&post, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return post, false
+	}
+
+	role, err := services.PostRole(post, c.MustGet("userID").(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check permissions"})
+		return post, false
+	}
+	if !services.RoleAtLeast(role, minimum) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to manage this post"})
+		return post, false
+	}
+
+	return post, true
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/services"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func toCommentResponse(comment models.Comment) gin.H {
+	return gin.H{
+		"id":           comment.ID,
+		"comment_text": comment.CommentText,
+		"post_id":      comment.PostID,
+		"parent_id":    comment.ParentID,
+		"user":         models.ToUserResponse(comment.User),
+		"created_at":   comment.CreatedAt,
+		"updated_at":   comment.UpdatedAt,
+	}
+}
+
+func GetPostComments(c *gin.Context) {
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return
+	}
+
+	var comments []models.Comment
+	if err := config.DB.Preload("User").
+		Where("post_id = ?", postID).
+		Order("created_at ASC").
+		Find(&comments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve comments"})
+		return
+	}
+
+	var response []gin.H
+	for _, comment := range comments {
+		response = append(response, toCommentResponse(comment))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+func CreateComment(c *gin.Context) {
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.First(&post, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	var request struct {
+		CommentText string `json:"comment_text" binding:"required"`
+		ParentID    *uint  `json:"parent_id"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil || strings.TrimSpace(request.CommentText) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment text is required"})
+		return
+	}
+
+	var parent models.Comment
+	if request.ParentID != nil {
+		if err := config.DB.Where("id = ? AND post_id = ?", *request.ParentID, post.ID).First(&parent).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parent comment not found"})
+			return
+		}
+	}
+
+	userID := c.MustGet("userID").(uint)
+	comment := models.Comment{
+		CommentText: request.CommentText,
+		UserID:      userID,
+		PostID:      post.ID,
+		ParentID:    request.ParentID,
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		return tx.Model(&post).Update("comment", gorm.Expr("comment + ?", 1)).Error
+	})
+	if err != nil {
+		log.Println("Error saving comment:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save comment"})
+		return
+	}
+
+	config.DB.Preload("User").First(&comment, comment.ID)
+	invalidatePostCache()
+	notifyComment(post, parent, comment)
+
+	commentResponse := toCommentResponse(comment)
+	services.PublishEvent(services.PostTopic(post.ID), "comment", commentResponse)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment created successfully", "data": commentResponse})
+}
+
+func notifyComment(post models.Post, parent models.Comment, comment models.Comment) {
+	var err error
+	if comment.ParentID != nil {
+		err = services.Notify(models.Notification{
+			UserID:    parent.UserID,
+			ActorID:   comment.UserID,
+			Type:      models.NotificationReply,
+			PostID:    &post.ID,
+			CommentID: &comment.ID,
+		})
+	}
+
+	// The post author hears about every comment, unless they were just told
+	// about it as the parent comment's author.
+	if err == nil && (comment.ParentID == nil || parent.UserID != post.UserID) {
+		err = services.Notify(models.Notification{
+			UserID:    post.UserID,
+			ActorID:   comment.UserID,
+			Type:      models.NotificationComment,
+			PostID:    &post.ID,
+			CommentID: &comment.ID,
+		})
+	}
+
+	if err == nil {
+		err = services.NotifyMentions(comment.UserID, comment.CommentText, nil, post.ID, &comment.ID)
+	}
+
+	if err != nil {
+		log.Println("Error creating comment notifications:", err)
+	}
+}
@@ -0,0 +1,241 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/responses"
+	"backend/services"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type pinState struct {
+	Pinned      bool       `json:"pinned"`
+	PinOrder    int        `json:"pin_order"`
+	PinnedUntil *time.Time `json:"pinned_until"`
+}
+
+func toPinState(post models.Post) pinState {
+	return pinState{Pinned: post.Pinned, PinOrder: post.PinOrder, PinnedUntil: post.PinnedUntil}
+}
+
+// GetFeaturedPosts returns the homepage's featured set in slot order,
+// skipping expired slots.
+func GetFeaturedPosts(c *gin.Context) {
+	page, err := cachedPostPage(c, postCachePrefix+"featured", func() ([]models.Post, int64, error) {
+		var slots []models.FeaturedSlot
+		if err := config.DB.Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+			Order("position ASC").
+			Find(&slots).Error; err != nil {
+			return nil, 0, err
+		}
+
+		postIDs := make([]uint, 0, len(slots))
+		for _, slot := range slots {
+			postIDs = append(postIDs, slot.PostID)
+		}
+
+		var posts []models.Post
+		if err := config.DB.Scopes(services.PreloadAuthors).
+			Preload("Tags").
+			Where("id IN ?", postIDs).
+			Find(&posts).Error; err != nil {
+			return nil, 0, err
+		}
+
+		postByID := make(map[uint]models.Post, len(posts))
+		for _, post := range posts {
+			postByID[post.ID] = post
+		}
+
+		ordered := make([]models.Post, 0, len(posts))
+		for _, postID := range postIDs {
+			if post, ok := postByID[postID]; ok {
+				ordered = append(ordered, post)
+			}
+		}
+		return ordered, int64(len(ordered)), nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve featured posts"})
+		return
+	}
+
+	applyViewerState(c, page.Data...)
+
+	responses.ConditionalJSON(c, gin.H{"data": page.Data}, time.Time{}, "")
+}
+
+// PinPost pins a post to the homepage. Pinned posts are listed by ascending
+// order; until, when set, unpins the post automatically.
+func PinPost(c *gin.Context) {
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.First(&post, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	var request struct {
+		Order int        `json:"order"`
+		Until *time.Time `json:"until"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.Until != nil && !request.Until.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "until must be in the future"})
+		return
+	}
+
+	before := toPinState(post)
+
+	if err := config.DB.Model(&post).Updates(map[string]interface{}{
+		"pinned":       true,
+		"pin_order":    request.Order,
+		"pinned_until": request.Until,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not pin post"})
+		return
+	}
+
+	services.SetAuditTarget(c, "post", post.ID, before, toPinState(post))
+	if !before.Pinned {
+		notifyCuration(c, post, "pinned your post to the homepage")
+	}
+	invalidatePostCache()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Post pinned successfully", "data": toPinState(post)})
+}
+
+func UnpinPost(c *gin.Context) {
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.First(&post, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	before := toPinState(post)
+
+	if err := config.DB.Model(&post).Updates(map[string]interface{}{
+		"pinned":       false,
+		"pin_order":    0,
+		"pinned_until": nil,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not unpin post"})
+		return
+	}
+
+	services.SetAuditTarget(c, "post", post.ID, before, toPinState(post))
+	invalidatePostCache()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Post unpinned successfully", "data": toPinState(post)})
+}
+
+// UpdateFeaturedPosts replaces the whole featured set. Slots are numbered in
+// the order given.
+func UpdateFeaturedPosts(c *gin.Context) {
+	var request struct {
+		Slots []struct {
+			PostID    uint       `json:"post_id" binding:"required"`
+			ExpiresAt *time.Time `json:"expires_at"`
+		} `json:"slots" binding:"required,dive"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	seen := make(map[uint]bool, len(request.Slots))
+	postIDs := make([]uint, 0, len(request.Slots))
+	for _, slot := range request.Slots {
+		if seen[slot.PostID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A post can only fill one featured slot"})
+			return
+		}
+		seen[slot.PostID] = true
+		postIDs = append(postIDs, slot.PostID)
+	}
+
+	var posts []models.Post
+	if err := config.DB.Where("id IN ?", postIDs).Find(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load posts"})
+		return
+	}
+	if len(posts) != len(postIDs) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Some posts do not exist"})
+		return
+	}
+
+	var previous []models.FeaturedSlot
+	if err := config.DB.Order("position ASC").Find(&previous).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load featured posts"})
+		return
+	}
+
+	adminID := c.MustGet("userID").(uint)
+	slots := make([]models.FeaturedSlot, 0, len(request.Slots))
+	for position, slot := range request.Slots {
+		slots = append(slots, models.FeaturedSlot{
+			Position:    position,
+			PostID:      slot.PostID,
+			ExpiresAt:   slot.ExpiresAt,
+			CreatedByID: adminID,
+		})
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.FeaturedSlot{}).Error; err != nil {
+			return err
+		}
+		if len(slots) == 0 {
+			return nil
+		}
+		return tx.Create(&slots).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update featured posts"})
+		return
+	}
+
+	services.SetAuditTarget(c, "featured", 0, previous, slots)
+
+	wasFeatured := make(map[uint]bool, len(previous))
+	for _, slot := range previous {
+		wasFeatured[slot.PostID] = true
+	}
+	for _, post := range posts {
+		if !wasFeatured[post.ID] {
+			notifyCuration(c, post, "featured your post on the homepage")
+		}
+	}
+	invalidatePostCache()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Featured posts updated successfully", "data": slots})
+}
+
+func notifyCuration(c *gin.Context, post models.Post, message string) {
+	if err := services.Notify(models.Notification{
+		UserID:  post.UserID,
+		ActorID: c.MustGet("userID").(uint),
+		Type:    models.NotificationEditorial,
+		PostID:  &post.ID,
+		Message: message,
+	}); err != nil {
+		log.Println("Error creating editorial notification:", err)
+	}
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/services"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const sseHeartbeatInterval = 25 * time.Second
+
+// StreamPostEvents pushes clap and comment updates for a post as Server-Sent Events.
+func StreamPostEvents(c *gin.Context) {
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.Select("id").First(&post, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	streamEvents(c, services.PostTopic(post.ID))
+}
+
+// StreamNotifications pushes the current user's notifications as Server-Sent Events.
+func StreamNotifications(c *gin.Context) {
+	streamEvents(c, services.UserTopic(c.MustGet("userID").(uint)))
+}
+
+func streamEvents(c *gin.Context, topic string) {
+	if services.Events == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Real-time events are not available"})
+		return
+	}
+
+	events, unsubscribe := services.Events.Subscribe(topic)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.SSEvent("ready", gin.H{"topic": topic})
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, string(event.Data))
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", time.Now().Unix())
+			return true
+		}
+	})
+}
@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/responses"
+	"backend/services"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func FollowUser(c *gin.Context) {
+	followeeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	if uint(followeeID) == userID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot follow yourself"})
+		return
+	}
+
+	var followee models.User
+	if err := config.DB.First(&followee, followeeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	follow := models.Follow{FollowerID: userID, FolloweeID: followee.ID}
+	result := config.DB.Where(&follow).FirstOrCreate(&follow)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not follow user"})
+		return
+	}
+
+	if result.RowsAffected > 0 {
+		if err := services.Notify(models.Notification{
+			UserID:  followee.ID,
+			ActorID: userID,
+			Type:    models.NotificationFollow,
+		}); err != nil {
+			log.Println("Error creating follow notification:", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Followed successfully"})
+}
+
+func UnfollowUser(c *gin.Context) {
+	followeeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	if err := config.DB.Where("follower_id = ? AND followee_id = ?", userID, followeeID).
+		Delete(&models.Follow{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not unfollow user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Unfollowed successfully"})
+}
+
+func FollowTag(c *gin.Context) {
+	var tag models.Tag
+	if err := config.DB.Where("name = ?", c.Param("name")).First(&tag).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	follow := models.TagFollow{UserID: userID, TagID: tag.ID}
+	if err := config.DB.Where(&follow).FirstOrCreate(&follow).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not follow tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Followed tag successfully"})
+}
+
+func UnfollowTag(c *gin.Context) {
+	var tag models.Tag
+	if err := config.DB.Where("name = ?", c.Param("name")).First(&tag).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	if err := config.DB.Where("user_id = ? AND tag_id = ?", userID, tag.ID).
+		Delete(&models.TagFollow{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not unfollow tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Unfollowed tag successfully"})
+}
+
+func GetUserProfile(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id", "user")
+	if !ok {
+		return
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	var followers, following int64
+	if err := config.DB.Model(&models.Follow{}).Where("followee_id = ?", user.ID).Count(&followers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not count followers"})
+		return
+	}
+	if err := config.DB.Model(&models.Follow{}).Where("follower_id = ?", user.ID).Count(&following).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not count following"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"user":            models.ToUserResponse(user),
+			"followers_count": followers,
+			"following_count": following,
+		},
+	})
+}
+
+func GetFollowers(c *gin.Context) {
+	listFollows(c, "followee_id", "follower_id")
+}
+
+func GetFollowing(c *gin.Context) {
+	listFollows(c, "follower_id", "followee_id")
+}
+
+// listFollows pages through the users on the other side of a follow edge.
+func listFollows(c *gin.Context, matchColumn, userColumn string) {
+	pageNum, perPageNum, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := parseIDParam(c, "id", "user")
+	if !ok {
+		return
+	}
+
+	edges := config.DB.Model(&models.Follow{}).Where(matchColumn+" = ?", userID)
+
+	var total int64
+	if err := edges.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not count users"})
+		return
+	}
+
+	var users []models.User
+	if err := config.DB.
+		Joins("JOIN follows ON follows."+userColumn+" = users.id").
+		Where("follows."+matchColumn+" = ?", userID).
+		Order("follows.created_at DESC").
+		Limit(perPageNum).
+		Offset((pageNum - 1) * perPageNum).
+		Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not fetch users"})
+		return
+	}
+
+	var response []models.UserResponse
+	for _, user := range users {
+		response = append(response, models.ToUserResponse(user))
+	}
+
+	responses.PaginateResponse(c, response, total, pageNum, perPageNum)
+}
+
+func GetFeed(c *gin.Context) {
+	pageNum, perPageNum, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	posts, total, err := services.BuildFeed(userID, time.Now(), pageNum, perPageNum)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not build feed"})
+		return
+	}
+
+	postResponses := toPostResponses(posts)
+	applyViewerState(c, postResponses...)
+
+	responses.PaginateResponse(c, postResponses, total, pageNum, perPageNum)
+}
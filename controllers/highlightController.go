@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/responses"
+	"backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CreateHighlight(c *gin.Context) {
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.First(&post, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	var request struct {
+		StartOffset *int `json:"start_offset" binding:"required"`
+		EndOffset   *int `json:"end_offset" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := services.QuoteAt(services.ContentText(post), *request.StartOffset, *request.EndOffset)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid highlight range"})
+		return
+	}
+
+	highlight := models.Highlight{
+		UserID:      c.MustGet("userID").(uint),
+		PostID:      post.ID,
+		StartOffset: *request.StartOffset,
+		EndOffset:   *request.EndOffset,
+		Quote:       quote,
+	}
+	if err := config.DB.Create(&highlight).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save highlight"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Highlight created successfully", "data": highlight})
+}
+
+// GetPostHighlights lists the current user's highlights on a post.
+func GetPostHighlights(c *gin.Context) {
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return
+	}
+
+	var highlights []models.Highlight
+	if err := config.DB.Where("post_id = ? AND user_id = ?", postID, c.MustGet("userID")).
+		Order("start_offset ASC").
+		Find(&highlights).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve highlights"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": highlights})
+}
+
+func GetMyHighlights(c *gin.Context) {
+	pageNum, perPageNum, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	query := config.DB.Model(&models.Highlight{}).Where("user_id = ?", c.MustGet("userID"))
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not count highlights"})
+		return
+	}
+
+	var highlights []models.Highlight
+	if err := query.Preload("Post").
+		Order("created_at DESC").
+		Limit(perPageNum).
+		Offset((pageNum - 1) * perPageNum).
+		Find(&highlights).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve highlights"})
+		return
+	}
+
+	var response []gin.H
+	for _, highlight := range highlights {
+		response = append(response, gin.H{
+			"id":           highlight.ID,
+			"start_offset": highlight.StartOffset,
+			"end_offset":   highlight.EndOffset,
+			"quote":        highlight.Quote,
+			"orphaned":     highlight.Orphaned,
+			"created_at":   highlight.CreatedAt,
+			"post": gin.H{
+				"id":    highlight.Post.ID,
+				"title": highlight.Post.Title,
+			},
+		})
+	}
+
+	responses.PaginateResponse(c, response, total, pageNum, perPageNum)
+}
+
+func DeleteHighlight(c *gin.Context) {
+	highlightID, ok := parseIDParam(c, "id", "highlight")
+	if !ok {
+		return
+	}
+
+	var highlight models.Highlight
+	if err := config.DB.First(&highlight, highlightID).Error; err != nil || highlight.UserID != c.MustGet("userID").(uint) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Highlight not found"})
+		return
+	}
+
+	if err := config.DB.Delete(&highlight).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete highlight"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Highlight deleted successfully"})
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/responses"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func GetNotifications(c *gin.Context) {
+	pageNum, perPageNum, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	query := config.DB.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if c.Query("unread") == "true" {
+		query = query.Where("is_read = ?", false)
+	}
+	if notificationType := c.Query("type"); notificationType != "" {
+		query = query.Where("type = ?", notificationType)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not count notifications"})
+		return
+	}
+
+	unread, err := unreadNotificationCount(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not count notifications"})
+		return
+	}
+
+	var notifications []models.Notification
+	if err := query.Preload("Actor").
+		Preload("Post").
+		Order("updated_at DESC, id DESC").
+		Limit(perPageNum).
+		Offset((pageNum - 1) * perPageNum).
+		Find(&notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve notifications"})
+		return
+	}
+
+	var response []gin.H
+	for _, notification := range notifications {
+		item := gin.H{
+			"id":         notification.ID,
+			"type":       notification.Type,
+			"actor":      models.ToUserResponse(notification.Actor),
+			"comment_id": notification.CommentID,
+			"message":    notification.Message,
+			"count":      notification.Count,
+			"read":       notification.Read,
+			"read_at":    notification.ReadAt,
+			"created_at": notification.CreatedAt,
+			"updated_at": notification.UpdatedAt,
+			"post":       nil,
+		}
+		if notification.Post != nil {
+			item["post"] = gin.H{"id": notification.Post.ID, "title": notification.Post.Title}
+		}
+		response = append(response, item)
+	}
+
+	responses.PaginateResponseWithMeta(c, response, total, pageNum, perPageNum, gin.H{"unread_count": unread})
+}
+
+func GetUnreadNotificationCount(c *gin.Context) {
+	unread, err := unreadNotificationCount(c.MustGet("userID").(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not count notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"unread_count": unread}})
+}
+
+func MarkNotificationRead(c *gin.Context) {
+	notificationID, ok := parseIDParam(c, "id", "notification")
+	if !ok {
+		return
+	}
+
+	result := config.DB.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", notificationID, c.MustGet("userID")).
+		Updates(map[string]interface{}{"is_read": true, "read_at": time.Now()})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update notification"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+func MarkAllNotificationsRead(c *gin.Context) {
+	if err := config.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", c.MustGet("userID"), false).
+		Updates(map[string]interface{}{"is_read": true, "read_at": time.Now()}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "All notifications marked as read"})
+}
+
+// GetNotificationPreferences returns every notification type with its
+// enabled flag; types the user never changed default to enabled.
+func GetNotificationPreferences(c *gin.Context) {
+	preferences, err := notificationPreferences(c.MustGet("userID").(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": preferences})
+}
+
+// UpdateNotificationPreferences accepts a map of type to enabled flag, e.g.
+// {"clap": false, "mention": true}.
+func UpdateNotificationPreferences(c *gin.Context) {
+	var request map[string]bool
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	valid := make(map[string]bool, len(models.NotificationTypes))
+	for _, notificationType := range models.NotificationTypes {
+		valid[notificationType] = true
+	}
+
+	userID := c.MustGet("userID").(uint)
+	var preferences []models.NotificationPreference
+	for notificationType, enabled := range request {
+		if !valid[notificationType] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown notification type: " + notificationType})
+			return
+		}
+		preferences = append(preferences, models.NotificationPreference{
+			UserID:  userID,
+			Type:    notificationType,
+			Enabled: enabled,
+		})
+	}
+
+	if len(preferences) > 0 {
+		if err := config.DB.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+		}).Create(&preferences).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update notification preferences"})
+			return
+		}
+	}
+
+	current, err := notificationPreferences(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification preferences updated", "data": current})
+}
+
+func notificationPreferences(userID uint) (map[string]bool, error) {
+	var stored []models.NotificationPreference
+	if err := config.DB.Where("user_id = ?", userID).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+
+	preferences := make(map[string]bool, len(models.NotificationTypes))
+	for _, notificationType := range models.NotificationTypes {
+		preferences[notificationType] = true
+	}
+	for _, preference := range stored {
+		preferences[preference.Type] = preference.Enabled
+	}
+
+	return preferences, nil
+}
+
+func unreadNotificationCount(userID uint) (int64, error) {
+	var unread int64
+	err := config.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Count(&unread).Error
+	return unread, err
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePagination reads page/per_page from the query string. On invalid input
+// it writes a 400 response and returns ok=false.
+func parsePagination(c *gin.Context) (page int, perPage int, ok bool) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page number"})
+		return 0, 0, false
+	}
+
+	perPage, err = strconv.Atoi(c.DefaultQuery("per_page", "10"))
+	if err != nil || perPage < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid per_page number"})
+		return 0, 0, false
+	}
+
+	if perPage > 100 {
+		perPage = 100
+	}
+
+	return page, perPage, true
+}
@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseIDParam reads a numeric ID from the named path parameter. On invalid
+// input it writes a 400 response and returns ok=false. Always query with the
+// parsed value: GORM treats a string passed as a bare condition as raw SQL.
+func parseIDParam(c *gin.Context, name string, label string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + label + " ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"backend/cache"
+	"backend/models"
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Every cached post listing lives under this prefix so that a single
+// invalidation clears them all when any post changes.
+const postCachePrefix = "posts:"
+
+// postPage is the anonymous (not personalized) form of a post listing as it
+// is stored in the cache. It has no Last-Modified: a listing also changes when
+// a post is deleted or drops off the page, which no timestamp in it reflects,
+// so listings are validated by ETag alone.
+type postPage struct {
+	Data  []map[string]interface{} `json:"data"`
+	Count int64                    `json:"count"`
+}
+
+// cachedPostPage serves a post listing from the cache, loading and storing it
+// on a miss.
+func cachedPostPage(c *gin.Context, key string, load func() ([]models.Post, int64, error)) (postPage, error) {
+	var page postPage
+
+	raw, err := cache.Remember(c.Request.Context(), key, func() ([]byte, error) {
+		posts, count, err := load()
+		if err != nil {
+			return nil, err
+		}
+
+		return json.Marshal(postPage{
+			Data:  toPostResponses(posts),
+			Count: count,
+		})
+	})
+	if err != nil {
+		return page, err
+	}
+
+	err = json.Unmarshal(raw, &page)
+	return page, err
+}
+
+func invalidatePostCache() {
+	cache.Invalidate(postCachePrefix)
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"backend/cache"
+	config "backend/configs"
+	"backend/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupPostCache points the controllers at an empty in-memory database and a
+// fresh cache, and returns a router serving the post listing and the
+// endpoints that change it, acting as the given user.
+func setupPostCache(t *testing.T) (*gin.Engine, models.User) {
+	gin.SetMode(gin.TestMode)
+
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.Post{}, &models.Highlight{},
+		&models.Notification{}, &models.NotificationPreference{},
+		&models.PostCollaborator{}, &models.Publication{}, &models.Series{}); err != nil {
+		t.Fatal(err)
+	}
+
+	previousDB, previousStore := config.DB, cache.Store
+	config.DB, cache.Store = db, cache.NewLRU(100)
+	t.Cleanup(func() {
+		config.DB, cache.Store = previousDB, previousStore
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	user := models.User{Name: "Author", Username: "author", Password: "x", RoleID: 2, Role: "user"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.GET("/posts", GetAllPosts)
+	authorized := router.Group("/", func(c *gin.Context) { c.Set("userID", user.ID) })
+	authorized.PUT("/posts/:id", UpdatePost)
+	authorized.DELETE("/posts/:id", DeletePost)
+
+	return router, user
+}
+
+func listPostTitles(t *testing.T, router *gin.Engine) []string {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/posts", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("GET /posts = %d: %s", recorder.Code, recorder.Body)
+	}
+
+	var body struct {
+		Data []struct {
+			Title string `json:"title"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+
+	titles := make([]string, 0, len(body.Data))
+	for _, post := range body.Data {
+		titles = append(titles, post.Title)
+	}
+	return titles
+}
+
+func createCachedPost(t *testing.T, router *gin.Engine, user models.User) models.Post {
+	t.Helper()
+
+	post := models.Post{Title: "Original", Description: "d", Content: "<p>Body</p>", UserID: user.ID}
+	if err := config.DB.Create(&post).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if titles := listPostTitles(t, router); len(titles) != 1 || titles[0] != "Original" {
+		t.Fatalf("listing = %v, want [Original]", titles)
+	}
+
+	// Change the row behind the cache's back: the listing must keep serving
+	// the cached page until a handler invalidates it.
+	config.DB.Model(&post).Update("title", "Changed directly")
+	if titles := listPostTitles(t, router); len(titles) != 1 || titles[0] != "Original" {
+		t.Fatalf("listing = %v, want the cached [Original]", titles)
+	}
+
+	return post
+}
+
+func TestUpdatePostInvalidatesListings(t *testing.T) {
+	router, user := setupPostCache(t)
+	post := createCachedPost(t, router, user)
+
+	request := httptest.NewRequest(http.MethodPut, "/posts/"+strconv.FormatUint(uint64(post.ID), 10),
+		strings.NewReader(`{"title":"Updated","description":"d","content":"<p>Body</p>"}`))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("If-Match", postETag(post))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("PUT /posts/%d = %d: %s", post.ID, recorder.Code, recorder.Body)
+	}
+
+	if titles := listPostTitles(t, router); len(titles) != 1 || titles[0] != "Updated" {
+		t.Fatalf("listing after update = %v, want [Updated]", titles)
+	}
+}
+
+func TestDeletePostInvalidatesListings(t *testing.T) {
+	router, user := setupPostCache(t)
+	post := createCachedPost(t, router, user)
+
+	request := httptest.NewRequest(http.MethodDelete, "/posts/"+strconv.FormatUint(uint64(post.ID), 10), nil)
+	request.Header.Set("If-Match", postETag(post))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("DELETE /posts/%d = %d: %s", post.ID, recorder.Code, recorder.Body)
+	}
+
+	if titles := listPostTitles(t, router); len(titles) != 0 {
+		t.Fatalf("listing after delete = %v, want none", titles)
+	}
+}
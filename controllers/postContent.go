@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/services"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// renderPostContent validates the format and renders the content, responding
+// 400 for an unknown format or an invalid block document and 500 when the
+// renderer itself fails.
+func renderPostContent(c *gin.Context, format, content string) (string, bool) {
+	if !services.ValidContentFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "content_format must be markdown, blocks or html"})
+		return "", false
+	}
+
+	contentHTML, err := services.RenderContent(format, content)
+	if errors.Is(err, services.ErrInvalidBlocks) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	if err != nil {
+		log.Println("Error rendering content:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not render content"})
+		return "", false
+	}
+
+	return contentHTML, true
+}
+
+// GetPostContent returns the post's content converted to ?format=html
+// (default), markdown or text.
+func GetPostContent(c *gin.Context) {
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.First(&post, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	target := c.DefaultQuery("format", services.ExportHTML)
+	if target != services.ExportHTML && target != services.ExportMarkdown && target != services.ExportText {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be html, markdown or text"})
+		return
+	}
+
+	content, err := services.ConvertContent(post, target)
+	if errors.Is(err, services.ErrUnsupportedExport) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Markdown is only available for posts written in Markdown or blocks"})
+		return
+	}
+	if err != nil {
+		log.Println("Error converting content:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not convert content"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"id":             post.ID,
+		"content_format": post.ContentFormat,
+		"format":         target,
+		"content":        content,
+	}})
+}
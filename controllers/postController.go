@@ -4,135 +4,100 @@ import (
 	config "backend/configs"
 	"backend/models"
 	"backend/responses"
+	"backend/services"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
+	"gorm.io/gorm"
 )
 
 func GetAllPosts(c *gin.Context) {
-	var posts []models.Post
-
-	page := c.DefaultQuery("page", "1")
-	perPage := c.DefaultQuery("per_page", "10")
-
-	pageNum, _ := strconv.Atoi(page)
-	perPageNum, _ := strconv.Atoi(perPage)
-
-	if perPageNum > 100 {
-		perPageNum = 100
-	}
-
-	var totalPosts int64
-	config.DB.Model(&models.Post{}).Count(&totalPosts)
-
-	offset := (pageNum - 1) * perPageNum
-
-	if err := config.DB.Preload("User").
-		Preload("Tags").
-		Order("created_at DESC").
-		Limit(perPageNum).
-		Offset(offset).
-		Find(&posts).Error; err != nil {
-		c.JSON(500, gin.H{"error": "Could not retrieve posts"})
+	pageNum, perPageNum, ok := parsePagination(c)
+	if !ok {
 		return
 	}
 
-	var postResponses []map[string]interface{}
-	for _, post := range posts {
-		userResponse := models.ToUserResponse(post.User)
-
-		postResponse := map[string]interface{}{
-			"id":          post.ID,
-			"title":       post.Title,
-			"description": post.Description,
-			"content":     post.Content,
-			"image":       post.Image,
-			"pinned":      post.Pinned,
-			"claps":       post.Claps,
-			"tags":        post.Tags,
-			"comment":     post.Comment,
-			"created_at":  post.CreatedAt,
-			"updated_at":  post.UpdatedAt,
-			"user":        userResponse,
+	key := fmt.Sprintf("%slist:page=%d:per_page=%d", postCachePrefix, pageNum, perPageNum)
+	page, err := cachedPostPage(c, key, func() ([]models.Post, int64, error) {
+		var totalPosts int64
+		if err := config.DB.Model(&models.Post{}).Count(&totalPosts).Error; err != nil {
+			return nil, 0, err
 		}
 
-		postResponses = append(postResponses, postResponse)
+		var posts []models.Post
+		err := config.DB.Scopes(services.PreloadAuthors).
+			Preload("Tags").
+			Order("created_at DESC").
+			Limit(perPageNum).
+			Offset((pageNum - 1) * perPageNum).
+			Find(&posts).Error
+		return posts, totalPosts, err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve posts"})
+		return
 	}
 
-	responses.PaginateResponse(c, postResponses, totalPosts, pageNum, perPageNum)
+	applyViewerState(c, page.Data...)
+
+	responses.ConditionalJSON(c, responses.PaginateBody(page.Data, page.Count, pageNum, perPageNum), time.Time{}, "")
 }
 
 func GetPinnedPosts(c *gin.Context) {
-	var posts []models.Post
-
-	if err := config.DB.Preload("User").
-		Preload("Tags").
-		Where("pinned = ?", true).
-		Order("claps DESC").
-		Find(&posts).Error; err != nil {
+	page, err := cachedPostPage(c, postCachePrefix+"pinned", func() ([]models.Post, int64, error) {
+		var posts []models.Post
+		err := config.DB.Scopes(services.PreloadAuthors).
+			Preload("Tags").
+			Where("pinned = ? AND (pinned_until IS NULL OR pinned_until > ?)", true, time.Now()).
+			Order("pin_order ASC, id ASC").
+			Find(&posts).Error
+		return posts, int64(len(posts)), err
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve pinned posts"})
 		return
 	}
 
-	var postResponses []map[string]interface{}
-	for _, post := range posts {
-		userResponse := models.ToUserResponse(post.User)
-
-		postResponse := map[string]interface{}{
-			"id":          post.ID,
-			"title":       post.Title,
-			"description": post.Description,
-			"content":     post.Content,
-			"image":       post.Image,
-			"pinned":      post.Pinned,
-			"claps":       post.Claps,
-			"tags":        post.Tags,
-			"comment":     post.Comment,
-			"created_at":  post.CreatedAt,
-			"updated_at":  post.UpdatedAt,
-			"user":        userResponse,
-		}
-
-		postResponses = append(postResponses, postResponse)
-	}
-
-	log.Println("postResponses", postResponses)
+	applyViewerState(c, page.Data...)
 
-	c.JSON(http.StatusOK, gin.H{
-		"data": postResponses,
-	})
+	responses.ConditionalJSON(c, gin.H{"data": page.Data}, time.Time{}, "")
 }
 
 func GetPostByID(c *gin.Context) {
 	postID := c.Param("id")
 	var post models.Post
 
-	if err := config.DB.Preload("User").Preload("Tags").Where("id = ?", postID).First(&post).Error; err != nil {
+	if err := config.DB.Scopes(services.PreloadAuthors).Preload("Tags").Where("id = ?", postID).First(&post).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
 		return
 	}
 
-	userResponse := models.ToUserResponse(post.User)
+	recordActivity(c, services.ActivityView, post.ID)
 
-	postResponse := map[string]interface{}{
-		"id":          post.ID,
-		"title":       post.Title,
-		"description": post.Description,
-		"content":     post.Content,
-		"image":       post.Image,
-		"pinned":      post.Pinned,
-		"claps":       post.Claps,
-		"tags":        post.Tags,
-		"comment":     post.Comment,
-		"created_at":  post.CreatedAt,
-		"updated_at":  post.UpdatedAt,
-		"user":        userResponse,
+	postResponse := toPostResponse(post)
+	applyViewerState(c, postResponse)
+
+	topHighlight, err := services.MostHighlightedPassage(post.ID)
+	if err != nil {
+		log.Println("Could not load top highlight:", err)
+	}
+	postResponse["top_highlight"] = topHighlight
+
+	series, err := services.NavigateSeries(post)
+	if err != nil {
+		log.Println("Could not load series navigation:", err)
 	}
+	postResponse["series"] = series
+	postResponse["toc"] = services.ParseTableOfContents(post.TableOfContents)
+	postResponse["seo"] = services.PostSEO(post, siteURL(c))
 
-	c.JSON(http.StatusOK, gin.H{"data": postResponse})
+	// The version prefix keeps the ETag usable for If-Match on updates.
+	responses.ConditionalJSON(c, gin.H{"data": postResponse}, post.UpdatedAt, postVersionTag(post))
 }
 
 func CreatePost(c *gin.Context) {
@@ -157,6 +122,26 @@ func CreatePost(c *gin.Context) {
 
 	userID := userIDInterface.(uint)
 
+	format := request.Format
+	if format == "" {
+		format = models.ContentFormatHTML
+	}
+	contentHTML, ok := renderPostContent(c, format, request.Content)
+	if !ok {
+		return
+	}
+
+	seo := services.SEOFields{
+		MetaTitle:       request.MetaTitle,
+		MetaDescription: request.MetaDescription,
+		CanonicalURL:    request.CanonicalURL,
+		OGImage:         request.OGImage,
+	}
+	if err := seo.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var tags []models.Tag
 	for _, tagName := range request.Tags {
 		var tag models.Tag
@@ -173,12 +158,18 @@ func CreatePost(c *gin.Context) {
 	}
 
 	post := models.Post{
-		Title:       request.Title,
-		Description: request.Description,
-		Content:     request.Content,
-		UserID:      userID,
-		Tags:        tags,
+		Title:           request.Title,
+		Description:     request.Description,
+		Content:         request.Content,
+		ContentFormat:   format,
+		ContentHTML:     contentHTML,
+		Image:           services.ContentCoverImage(format, request.Content),
+		TableOfContents: services.TableOfContents(contentHTML),
+		UserID:          userID,
+		Tags:            tags,
 	}
+	services.ComputeReadingStats(format, request.Content, contentHTML, request.Description).Apply(&post)
+	seo.Apply(&post)
 
 	log.Println("userData.ID", userID)
 
@@ -188,6 +179,12 @@ func CreatePost(c *gin.Context) {
 		return
 	}
 
+	if err := services.NotifyMentions(userID, services.ContentText(post), nil, post.ID, nil); err != nil {
+		log.Println("Error creating mention notifications:", err)
+	}
+
+	invalidatePostCache()
+
 	c.JSON(http.StatusOK, gin.H{"message": "Post created successfully", "post": post})
 }
 
@@ -200,8 +197,12 @@ func UpdatePost(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("userID")
-	if !exists || post.UserID != userID {
+	role, err := services.PostRole(post, c.MustGet("userID").(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check permissions"})
+		return
+	}
+	if !services.RoleAtLeast(role, models.CollaboratorEditor) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "You do not have permission to update this post"})
 		return
 	}
@@ -212,14 +213,82 @@ func UpdatePost(c *gin.Context) {
 		return
 	}
 
-	post.Title = updatedPost.Title
-	post.Content = updatedPost.Content
-	post.Image = updatedPost.Image
+	expectedVersion, ok := requirePostVersion(c, post, updatedPost.Version)
+	if !ok {
+		return
+	}
+
+	if updatedPost.ContentFormat == "" {
+		updatedPost.ContentFormat = post.ContentFormat
+	}
+	contentHTML, ok := renderPostContent(c, updatedPost.ContentFormat, updatedPost.Content)
+	if !ok {
+		return
+	}
+	if updatedPost.Image == "" {
+		updatedPost.Image = services.ContentCoverImage(updatedPost.ContentFormat, updatedPost.Content)
+	}
+
+	seo := services.SEOFields{
+		MetaTitle:       updatedPost.MetaTitle,
+		MetaDescription: updatedPost.MetaDescription,
+		CanonicalURL:    updatedPost.CanonicalURL,
+		OGImage:         updatedPost.OGImage,
+	}
+	if err := seo.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stats := services.ComputeReadingStats(updatedPost.ContentFormat, updatedPost.Content, contentHTML, post.Description)
+
+	contentChanged := post.Content != updatedPost.Content
+	previousMentions := services.ParseMentions(services.ContentText(post))
+
+	result := config.DB.Model(&models.Post{}).
+		Where("id = ? AND version = ?", post.ID, expectedVersion).
+		Updates(map[string]interface{}{
+			"title":            updatedPost.Title,
+			"content":          updatedPost.Content,
+			"content_format":   updatedPost.ContentFormat,
+			"content_html":     contentHTML,
+			"word_count":       stats.WordCount,
+			"reading_minutes":  stats.ReadingMinutes,
+			"excerpt":          stats.Excerpt,
+			"toc":              services.TableOfContents(contentHTML),
+			"image":            updatedPost.Image,
+			"meta_title":       seo.MetaTitle,
+			"meta_description": seo.MetaDescription,
+			"canonical_url":    seo.CanonicalURL,
+			"og_image":         seo.OGImage,
+			"version":          gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update post"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		respondVersionConflict(c, post.ID)
+		return
+	}
 
-	if err := config.DB.Save(&post).Error; err != nil {
+	if err := config.DB.First(&post, post.ID).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update post"})
 		return
 	}
+	c.Header("ETag", postETag(post))
+
+	if contentChanged {
+		text := services.ContentText(post)
+		if err := services.ReanchorHighlights(post.ID, text); err != nil {
+			log.Println("Error re-anchoring highlights:", err)
+		}
+		if err := services.NotifyMentions(c.MustGet("userID").(uint), text, previousMentions, post.ID, nil); err != nil {
+			log.Println("Error creating mention notifications:", err)
+		}
+	}
+
+	invalidatePostCache()
 
 	c.JSON(http.StatusOK, gin.H{"message": "Post updated successfully", "post": post})
 }
@@ -233,16 +302,37 @@ func DeletePost(c *gin.Context) {
 		return
 	}
 
-	userID, exists := c.Get("userID")
-	if !exists || post.UserID != userID {
+	role, err := services.PostRole(post, c.MustGet("userID").(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check permissions"})
+		return
+	}
+	if !services.RoleAtLeast(role, models.CollaboratorOwner) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "You do not have permission to delete this post"})
 		return
 	}
 
-	if err := config.DB.Delete(&post).Error; err != nil {
+	var bodyVersion uint
+	if version, err := strconv.ParseUint(c.Query("version"), 10, 32); err == nil {
+		bodyVersion = uint(version)
+	}
+
+	expectedVersion, ok := requirePostVersion(c, post, bodyVersion)
+	if !ok {
+		return
+	}
+
+	result := config.DB.Where("version = ?", expectedVersion).Delete(&post)
+	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete post"})
 		return
 	}
+	if result.RowsAffected == 0 {
+		respondVersionConflict(c, post.ID)
+		return
+	}
+
+	invalidatePostCache()
 
 	c.JSON(http.StatusOK, gin.H{"message": "Post deleted successfully"})
 }
@@ -256,10 +346,10 @@ func ClapPost(c *gin.Context) {
 	}
 
 	var requestBody struct {
-		Claps int `json:"claps"`
+		Claps int `json:"claps" binding:"min=1,max=50"`
 	}
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "claps must be between 1 and 50"})
 		return
 	}
 
@@ -269,12 +359,29 @@ func ClapPost(c *gin.Context) {
 		return
 	}
 
-	post.Claps += uint(requestBody.Claps)
+	userID := c.MustGet("userID").(uint)
 
-	if err := config.DB.Save(&post).Error; err != nil {
+	// Claps are incremented in place so they never bump the version or
+	// overwrite a concurrent edit of the post. Each clap is also logged so
+	// rankings can count claps within a time window.
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&post).Update("claps", gorm.Expr("claps + ?", requestBody.Claps)).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.Clap{UserID: userID, PostID: post.ID, Count: requestBody.Claps}).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update claps"})
 		return
 	}
+	config.DB.Model(&post).Select("claps").First(&post)
+
+	if err := services.NotifyClap(post.UserID, userID, post.ID, requestBody.Claps); err != nil {
+		log.Println("Error creating clap notification:", err)
+	}
+
+	invalidatePostCache()
+	services.PublishEvent(services.PostTopic(post.ID), "clap", gin.H{"post_id": post.ID, "claps": post.Claps})
 
 	c.JSON(http.StatusOK, gin.H{
 		"data": gin.H{
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"backend/models"
+	"backend/services"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func toPostResponse(post models.Post) map[string]interface{} {
+	return map[string]interface{}{
+		"id":               post.ID,
+		"title":            post.Title,
+		"description":      post.Description,
+		"content":          post.Content,
+		"content_format":   post.ContentFormat,
+		"content_html":     post.ContentHTML,
+		"word_count":       post.WordCount,
+		"reading_minutes":  post.ReadingMinutes,
+		"excerpt":          post.Excerpt,
+		"image":            post.Image,
+		"meta_title":       post.MetaTitle,
+		"meta_description": post.MetaDescription,
+		"canonical_url":    post.CanonicalURL,
+		"og_image":         post.OGImage,
+		"pinned":           post.Pinned,
+		"claps":            post.Claps,
+		"tags":             post.Tags,
+		"comment":          post.Comment,
+		"views":            post.Views,
+		"reads":            post.Reads,
+		"version":          post.Version,
+		"publication_id":   post.PublicationID,
+		"created_at":       post.CreatedAt,
+		"updated_at":       post.UpdatedAt,
+		"user":             models.ToUserResponse(post.User),
+		"authors":          postAuthors(post),
+	}
+}
+
+// postAuthors lists the original author followed by the accepted co-authors
+// that were preloaded into post.Collaborators.
+func postAuthors(post models.Post) []models.UserResponse {
+	authors := []models.UserResponse{models.ToUserResponse(post.User)}
+	for _, collaborator := range post.Collaborators {
+		authors = append(authors, models.ToUserResponse(collaborator.User))
+	}
+	return authors
+}
+
+func toPostResponses(posts []models.Post) []map[string]interface{} {
+	var postResponses []map[string]interface{}
+	for _, post := range posts {
+		postResponses = append(postResponses, toPostResponse(post))
+	}
+	return postResponses
+}
+
+// applyViewerState adds per-user fields such as "bookmarked" to post payloads
+// when the request is authenticated. Anonymous payloads are left untouched.
+func applyViewerState(c *gin.Context, postResponses ...map[string]interface{}) {
+	userIDValue, exists := c.Get("userID")
+	if !exists || len(postResponses) == 0 {
+		return
+	}
+	userID := userIDValue.(uint)
+
+	postIDs := make([]uint, 0, len(postResponses))
+	for _, postResponse := range postResponses {
+		postIDs = append(postIDs, postResponseID(postResponse))
+	}
+
+	bookmarked, err := services.BookmarkedPostIDs(userID, postIDs)
+	if err != nil {
+		log.Println("Could not load bookmark state:", err)
+		return
+	}
+
+	for _, postResponse := range postResponses {
+		postResponse["bookmarked"] = bookmarked[postResponseID(postResponse)]
+	}
+}
+
+// latestUpdate returns the most recent UpdatedAt, used as Last-Modified.
+func latestUpdate(posts []models.Post) time.Time {
+	var latest time.Time
+	for _, post := range posts {
+		if post.UpdatedAt.After(latest) {
+			latest = post.UpdatedAt
+		}
+	}
+	return latest
+}
+
+// postResponseID reads the post ID from a payload built by toPostResponse,
+// including payloads that went through JSON (e.g. from the cache).
+func postResponseID(postResponse map[string]interface{}) uint {
+	switch id := postResponse["id"].(type) {
+	case uint:
+		return id
+	case float64:
+		return uint(id)
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func postVersionTag(post models.Post) string {
+	return fmt.Sprintf("v%d", post.Version)
+}
+
+// postETag identifies a revision of a post's editable fields.
+func postETag(post models.Post) string {
+	return `"` + postVersionTag(post) + `"`
+}
+
+// parseVersionETag extracts the version from an ETag produced by postETag, or
+// by GetPostByID, which appends a hash of the payload ("v3-1a2b...").
+func parseVersionETag(etag string) (uint, bool) {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	etag = strings.Trim(etag, `"`)
+	if !strings.HasPrefix(etag, "v") {
+		return 0, false
+	}
+
+	tag, _, _ := strings.Cut(etag[1:], "-")
+	version, err := strconv.ParseUint(tag, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint(version), true
+}
+
+// requirePostVersion returns the version the client based its change on,
+// taken from If-Match or, failing that, from fallback (a "version" field).
+// It responds 428 when neither is given and 412 when the version is stale.
+func requirePostVersion(c *gin.Context, post models.Post, fallback uint) (uint, bool) {
+	expected := fallback
+
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		if strings.TrimSpace(ifMatch) == "*" {
+			return post.Version, true
+		}
+
+		version, ok := parseVersionETag(ifMatch)
+		if !ok {
+			respondVersionConflict(c, post.ID)
+			return 0, false
+		}
+		expected = version
+	}
+
+	if expected == 0 {
+		c.JSON(http.StatusPreconditionRequired, gin.H{
+			"error":           "If-Match header or version is required",
+			"current_version": post.Version,
+		})
+		return 0, false
+	}
+
+	if expected != post.Version {
+		respondVersionConflict(c, post.ID)
+		return 0, false
+	}
+
+	return expected, true
+}
+
+func respondVersionConflict(c *gin.Context, postID uint) {
+	var current models.Post
+	if err := config.DB.Select("id", "version").First(&current, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	c.Header("ETag", postETag(current))
+	c.JSON(http.StatusPreconditionFailed, gin.H{
+		"error":           "Post has been modified by someone else",
+		"current_version": current.Version,
+	})
+}
@@ -0,0 +1,655 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/responses"
+	"backend/services"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type publicationRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Slug        string `json:"slug" binding:"max=100"`
+	Logo        string `json:"logo" binding:"max=255"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+func toPublicationResponse(publication models.Publication) gin.H {
+	return gin.H{
+		"id":          publication.ID,
+		"name":        publication.Name,
+		"slug":        publication.Slug,
+		"logo":        publication.Logo,
+		"description": publication.Description,
+		"created_at":  publication.CreatedAt,
+		"updated_at":  publication.UpdatedAt,
+	}
+}
+
+func toPublicationMemberResponse(member models.PublicationMember) gin.H {
+	return gin.H{
+		"user":      models.ToUserResponse(member.User),
+		"role":      member.Role,
+		"joined_at": member.CreatedAt,
+	}
+}
+
+// GetPublication returns the publication, its members and its published
+// posts, pinned ones first. ?featured=true narrows the posts to featured ones.
+func GetPublication(c *gin.Context) {
+	publication, ok := findPublication(c)
+	if !ok {
+		return
+	}
+
+	pageNum, perPageNum, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	var members []models.PublicationMember
+	if err := config.DB.Preload("User").
+		Where("publication_id = ?", publication.ID).
+		Order("id ASC").
+		Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve publication"})
+		return
+	}
+
+	query := config.DB.Model(&models.Post{}).
+		Where("publication_id = ? AND publication_status = ?", publication.ID, models.PublicationPublished)
+	if c.Query("featured") == "true" {
+		query = query.Where("publication_featured = ?", true)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve posts"})
+		return
+	}
+
+	var posts []models.Post
+	if err := query.Scopes(services.PreloadAuthors).
+		Preload("Tags").
+		Order("publication_pinned DESC, publication_featured DESC, created_at DESC").
+		Limit(perPageNum).
+		Offset((pageNum - 1) * perPageNum).
+		Find(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve posts"})
+		return
+	}
+
+	postResponses := toPostResponses(posts)
+	for i, post := range posts {
+		postResponses[i]["publication_pinned"] = post.PublicationPinned
+		postResponses[i]["publication_featured"] = post.PublicationFeatured
+	}
+	applyViewerState(c, postResponses...)
+
+	memberResponses := make([]gin.H, 0, len(members))
+	for _, member := range members {
+		memberResponses = append(memberResponses, toPublicationMemberResponse(member))
+	}
+
+	publicationResponse := toPublicationResponse(publication)
+	publicationResponse["members"] = memberResponses
+
+	responses.PaginateResponseWithMeta(c, postResponses, total, pageNum, perPageNum, gin.H{"publication": publicationResponse})
+}
+
+func GetMyPublications(c *gin.Context) {
+	var members []models.PublicationMember
+	if err := config.DB.Where("user_id = ?", c.MustGet("userID").(uint)).Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve publications"})
+		return
+	}
+
+	roles := make(map[uint]string)
+	publicationIDs := make([]uint, 0, len(members))
+	for _, member := range members {
+		roles[member.PublicationID] = member.Role
+		publicationIDs = append(publicationIDs, member.PublicationID)
+	}
+
+	var publications []models.Publication
+	if err := config.DB.Where("id IN ?", publicationIDs).Order("name ASC").Find(&publications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve publications"})
+		return
+	}
+
+	var response []gin.H
+	for _, publication := range publications {
+		publicationResponse := toPublicationResponse(publication)
+		publicationResponse["role"] = roles[publication.ID]
+		response = append(response, publicationResponse)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+// CreatePublication creates a publication owned by the current user. The slug
+// defaults to one derived from the name.
+func CreatePublication(c *gin.Context) {
+	var request publicationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slug := services.Slugify(request.Slug)
+	if slug == "" {
+		slug = services.Slugify(request.Name)
+	}
+	if slug == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A slug with letters or digits is required"})
+		return
+	}
+
+	var existing int64
+	if err := config.DB.Model(&models.Publication{}).Where("slug = ?", slug).Count(&existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create publication"})
+		return
+	}
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug is already taken"})
+		return
+	}
+
+	publication := models.Publication{
+		Name:        request.Name,
+		Slug:        slug,
+		Logo:        request.Logo,
+		Description: request.Description,
+	}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&publication).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.PublicationMember{
+			PublicationID: publication.ID,
+			UserID:        c.MustGet("userID").(uint),
+			Role:          models.PublicationOwner,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create publication"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Publication created successfully", "data": toPublicationResponse(publication)})
+}
+
+// UpdatePublication changes the name, logo and description. The slug is kept
+// so existing links keep working.
+func UpdatePublication(c *gin.Context) {
+	publication, ok := findPublicationWithRole(c, models.PublicationOwner)
+	if !ok {
+		return
+	}
+
+	var request publicationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	publication.Name = request.Name
+	publication.Logo = request.Logo
+	publication.Description = request.Description
+
+	if err := config.DB.Save(&publication).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update publication"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Publication updated successfully", "data": toPublicationResponse(publication)})
+}
+
+func AddPublicationMember(c *gin.Context) {
+	publication, ok := findPublicationWithRole(c, models.PublicationOwner)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		UserID uint   `json:"user_id" binding:"required"`
+		Role   string `json:"role"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if request.Role == "" {
+		request.Role = models.PublicationWriter
+	}
+	if !services.ValidPublicationRole(request.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be one of owner, editor or writer"})
+		return
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, request.UserID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	member := models.PublicationMember{PublicationID: publication.ID, UserID: user.ID}
+	if err := config.DB.Where(&member).
+		Attrs(models.PublicationMember{Role: request.Role}).
+		FirstOrCreate(&member).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not add member"})
+		return
+	}
+
+	member.User = user
+	c.JSON(http.StatusOK, gin.H{"message": "Member added successfully", "data": toPublicationMemberResponse(member)})
+}
+
+func UpdatePublicationMember(c *gin.Context) {
+	publication, ok := findPublicationWithRole(c, models.PublicationOwner)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil || !services.ValidPublicationRole(request.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be one of owner, editor or writer"})
+		return
+	}
+
+	member, ok := findPublicationMember(c, publication)
+	if !ok {
+		return
+	}
+
+	if member.Role == models.PublicationOwner && request.Role != models.PublicationOwner && !keepsAnOwner(c, publication) {
+		return
+	}
+
+	if err := config.DB.Model(&member).Update("role", request.Role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member updated successfully", "data": toPublicationMemberResponse(member)})
+}
+
+// RemovePublicationMember removes a member. Owners can remove anyone; any
+// member can remove themselves to leave the publication.
+func RemovePublicationMember(c *gin.Context) {
+	memberID, ok := parseIDParam(c, "userId", "user")
+	if !ok {
+		return
+	}
+
+	minimum := models.PublicationOwner
+	if memberID == c.MustGet("userID").(uint) {
+		minimum = models.PublicationWriter
+	}
+
+	publication, ok := findPublicationWithRole(c, minimum)
+	if !ok {
+		return
+	}
+
+	member, ok := findPublicationMember(c, publication)
+	if !ok {
+		return
+	}
+
+	if member.Role == models.PublicationOwner && !keepsAnOwner(c, publication) {
+		return
+	}
+
+	if err := config.DB.Delete(&member).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not remove member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
+
+// SubmitPostToPublication places one of the user's posts in a publication.
+// Posts from writers wait for an editor's review; editors and owners publish
+// directly.
+func SubmitPostToPublication(c *gin.Context) {
+	post, ok := findPostWithRole(c, models.CollaboratorOwner)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Publication string `json:"publication" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var publication models.Publication
+	if err := config.DB.Where("slug = ?", request.Publication).First(&publication).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Publication not found"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	role, err := services.PublicationRole(publication.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check permissions"})
+		return
+	}
+	if role == "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only members can submit posts to this publication"})
+		return
+	}
+
+	status := models.PublicationSubmitted
+	if services.PublicationRoleAtLeast(role, models.PublicationEditor) {
+		status = models.PublicationPublished
+	}
+
+	if err := config.DB.Model(&post).Updates(map[string]interface{}{
+		"publication_id":       publication.ID,
+		"publication_status":   status,
+		"publication_pinned":   false,
+		"publication_featured": false,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not submit post"})
+		return
+	}
+
+	if status == models.PublicationSubmitted {
+		services.NotifyPublicationEditors(publication.ID, userID, post.ID,
+			fmt.Sprintf("submitted %q to %s", post.Title, publication.Name))
+	}
+	invalidatePostCache()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Post submitted successfully", "data": gin.H{
+		"post_id":            post.ID,
+		"publication":        toPublicationResponse(publication),
+		"publication_status": status,
+	}})
+}
+
+// publicationState is the audit snapshot of a post's place in a publication.
+type publicationState struct {
+	PublicationID     *uint  `json:"publication_id"`
+	PublicationStatus string `json:"publication_status"`
+}
+
+func toPublicationState(post models.Post) publicationState {
+	return publicationState{PublicationID: post.PublicationID, PublicationStatus: post.PublicationStatus}
+}
+
+// WithdrawPostFromPublication takes a post out of its publication. Either the
+// post's owner or an editor of the publication can do it.
+func WithdrawPostFromPublication(c *gin.Context) {
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.First(&post, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+	if post.PublicationID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Post is not in a publication"})
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+	postRole, err := services.PostRole(post, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check permissions"})
+		return
+	}
+	publicationRole, err := services.PublicationRole(*post.PublicationID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check permissions"})
+		return
+	}
+	if !services.RoleAtLeast(postRole, models.CollaboratorOwner) &&
+		!services.PublicationRoleAtLeast(publicationRole, models.PublicationEditor) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to manage this post"})
+		return
+	}
+
+	before := toPublicationState(post)
+
+	if err := config.DB.Model(&post).Updates(map[string]interface{}{
+		"publication_id":       nil,
+		"publication_status":   "",
+		"publication_pinned":   false,
+		"publication_featured": false,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not withdraw post"})
+		return
+	}
+	services.SetAuditTarget(c, "post", post.ID, before, toPublicationState(post))
+	invalidatePostCache()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Post withdrawn successfully"})
+}
+
+func GetPublicationSubmissions(c *gin.Context) {
+	publication, ok := findPublicationWithRole(c, models.PublicationEditor)
+	if !ok {
+		return
+	}
+
+	var posts []models.Post
+	if err := config.DB.Scopes(services.PreloadAuthors).
+		Preload("Tags").
+		Where("publication_id = ? AND publication_status = ?", publication.ID, models.PublicationSubmitted).
+		Order("updated_at ASC").
+		Find(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve submissions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": toPostResponses(posts)})
+}
+
+func ApprovePublicationPost(c *gin.Context) {
+	reviewPublicationPost(c, models.PublicationPublished, "published %q in %s")
+}
+
+func RejectPublicationPost(c *gin.Context) {
+	reviewPublicationPost(c, models.PublicationRejected, "declined %q for %s")
+}
+
+func reviewPublicationPost(c *gin.Context, status string, message string) {
+	publication, ok := findPublicationWithRole(c, models.PublicationEditor)
+	if !ok {
+		return
+	}
+
+	post, ok := findPublicationPost(c, publication)
+	if !ok {
+		return
+	}
+
+	if post.PublicationStatus != models.PublicationSubmitted {
+		c.JSON(http.StatusConflict, gin.H{"error": "Post is not awaiting review"})
+		return
+	}
+
+	before := toPublicationState(post)
+	if err := config.DB.Model(&post).Update("publication_status", status).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not review post"})
+		return
+	}
+	services.SetAuditTarget(c, "post", post.ID, before, toPublicationState(post))
+
+	if err := services.Notify(models.Notification{
+		UserID:  post.UserID,
+		ActorID: c.MustGet("userID").(uint),
+		Type:    models.NotificationEditorial,
+		PostID:  &post.ID,
+		Message: fmt.Sprintf(message, post.Title, publication.Name),
+	}); err != nil {
+		log.Println("Error creating editorial notification:", err)
+	}
+	invalidatePostCache()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Post " + status, "data": gin.H{
+		"post_id":            post.ID,
+		"publication_status": status,
+	}})
+}
+
+// UpdatePublicationPost lets editors pin or feature a published post inside
+// their publication.
+func UpdatePublicationPost(c *gin.Context) {
+	publication, ok := findPublicationWithRole(c, models.PublicationEditor)
+	if !ok {
+		return
+	}
+
+	post, ok := findPublicationPost(c, publication)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Pinned   *bool `json:"pinned"`
+		Featured *bool `json:"featured"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if post.PublicationStatus != models.PublicationPublished {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only published posts can be pinned or featured"})
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if request.Pinned != nil {
+		updates["publication_pinned"] = *request.Pinned
+	}
+	if request.Featured != nil {
+		updates["publication_featured"] = *request.Featured
+	}
+	if len(updates) > 0 {
+		if err := config.DB.Model(&post).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update post"})
+			return
+		}
+		invalidatePostCache()
+	}
+
+	if request.Featured != nil && *request.Featured {
+		if err := services.Notify(models.Notification{
+			UserID:  post.UserID,
+			ActorID: c.MustGet("userID").(uint),
+			Type:    models.NotificationEditorial,
+			PostID:  &post.ID,
+			Message: fmt.Sprintf("featured %q in %s", post.Title, publication.Name),
+		}); err != nil {
+			log.Println("Error creating editorial notification:", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Post updated successfully", "data": gin.H{
+		"post_id":              post.ID,
+		"publication_pinned":   post.PublicationPinned,
+		"publication_featured": post.PublicationFeatured,
+	}})
+}
+
+func findPublication(c *gin.Context) (models.Publication, bool) {
+	var publication models.Publication
+	if err := config.DB.Where("slug = ?", c.Param("slug")).First(&publication).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Publication not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve publication"})
+		}
+		return publication, false
+	}
+	return publication, true
+}
+
+// findPublicationWithRole loads the publication from the :slug param and
+// checks that the current user holds at least the given role in it.
+func findPublicationWithRole(c *gin.Context, minimum string) (models.Publication, bool) {
+	publication, ok := findPublication(c)
+	if !ok {
+		return publication, false
+	}
+
+	role, err := services.PublicationRole(publication.ID, c.MustGet("userID").(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check permissions"})
+		return publication, false
+	}
+	if !services.PublicationRoleAtLeast(role, minimum) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to manage this publication"})
+		return publication, false
+	}
+
+	return publication, true
+}
+
+func findPublicationMember(c *gin.Context, publication models.Publication) (models.PublicationMember, bool) {
+	var member models.PublicationMember
+
+	memberID, ok := parseIDParam(c, "userId", "user")
+	if !ok {
+		return member, false
+	}
+
+	if err := config.DB.Preload("User").
+		Where("publication_id = ? AND user_id = ?", publication.ID, memberID).
+		First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return member, false
+	}
+	return member, true
+}
+
+func findPublicationPost(c *gin.Context, publication models.Publication) (models.Post, bool) {
+	var post models.Post
+
+	postID, ok := parseIDParam(c, "postId", "post")
+	if !ok {
+		return post, false
+	}
+
+	if err := config.DB.Where("id = ? AND publication_id = ?", postID, publication.ID).
+		First(&post).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found in this publication"})
+		return post, false
+	}
+	return post, true
+}
+
+// keepsAnOwner rejects changes that would leave the publication without an
+// owner.
+func keepsAnOwner(c *gin.Context, publication models.Publication) bool {
+	var owners int64
+	if err := config.DB.Model(&models.PublicationMember{}).
+		Where("publication_id = ? AND role = ?", publication.ID, models.PublicationOwner).
+		Count(&owners).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check owners"})
+		return false
+	}
+	if owners <= 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A publication needs at least one owner"})
+		return false
+	}
+	return true
+}
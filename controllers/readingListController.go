@@ -0,0 +1,335 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type readingListRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+	Public      bool   `json:"public"`
+}
+
+func GetMyReadingLists(c *gin.Context) {
+	userID := c.MustGet("userID").(uint)
+
+	if _, err := services.DefaultReadingList(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load reading lists"})
+		return
+	}
+
+	var lists []models.ReadingList
+	if err := config.DB.Where("user_id = ?", userID).
+		Order("is_default DESC, created_at ASC").
+		Find(&lists).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load reading lists"})
+		return
+	}
+
+	type itemCount struct {
+		ReadingListID uint
+		Count         int64
+	}
+	var counts []itemCount
+	if err := config.DB.Model(&models.ReadingListItem{}).
+		Select("reading_list_id, COUNT(*) AS count").
+		Joins("JOIN reading_lists ON reading_lists.id = reading_list_items.reading_list_id").
+		Where("reading_lists.user_id = ?", userID).
+		Group("reading_list_id").
+		Scan(&counts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load reading lists"})
+		return
+	}
+
+	countByList := make(map[uint]int64)
+	for _, count := range counts {
+		countByList[count.ReadingListID] = count.Count
+	}
+
+	var response []gin.H
+	for _, list := range lists {
+		response = append(response, gin.H{
+			"id":          list.ID,
+			"name":        list.Name,
+			"description": list.Description,
+			"public":      list.Public,
+			"is_default":  list.IsDefault,
+			"item_count":  countByList[list.ID],
+			"created_at":  list.CreatedAt,
+			"updated_at":  list.UpdatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+func CreateReadingList(c *gin.Context) {
+	var request readingListRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	list := models.ReadingList{
+		UserID:      c.MustGet("userID").(uint),
+		Name:        request.Name,
+		Description: request.Description,
+		Public:      request.Public,
+	}
+	if err := config.DB.Create(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create reading list"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reading list created successfully", "data": list})
+}
+
+func UpdateReadingList(c *gin.Context) {
+	list, ok := findOwnReadingList(c)
+	if !ok {
+		return
+	}
+
+	var request readingListRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	list.Name = request.Name
+	list.Description = request.Description
+	list.Public = request.Public
+
+	if err := config.DB.Save(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update reading list"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reading list updated successfully", "data": list})
+}
+
+func DeleteReadingList(c *gin.Context) {
+	list, ok := findOwnReadingList(c)
+	if !ok {
+		return
+	}
+
+	if list.IsDefault {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The default reading list cannot be deleted"})
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("reading_list_id = ?", list.ID).Delete(&models.ReadingListItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&list).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete reading list"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reading list deleted successfully"})
+}
+
+// GetReadingList shows a list to its owner, or to anyone when it is public.
+func GetReadingList(c *gin.Context) {
+	listID, ok := parseIDParam(c, "id", "reading list")
+	if !ok {
+		return
+	}
+
+	var list models.ReadingList
+	if err := config.DB.Preload("User").First(&list, listID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reading list not found"})
+		return
+	}
+
+	userID, loggedIn := c.Get("userID")
+	if !list.Public && (!loggedIn || userID.(uint) != list.UserID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reading list not found"})
+		return
+	}
+
+	var items []models.ReadingListItem
+	if err := config.DB.Preload("Post.User").
+		Preload("Post.Collaborators", services.AuthorCollaborators).
+		Preload("Post.Collaborators.User").
+		Preload("Post.Tags").
+		Where("reading_list_id = ?", list.ID).
+		Order("position ASC, id ASC").
+		Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load reading list items"})
+		return
+	}
+
+	posts := make([]models.Post, 0, len(items))
+	for _, item := range items {
+		posts = append(posts, item.Post)
+	}
+	postResponses := toPostResponses(posts)
+	applyViewerState(c, postResponses...)
+
+	itemResponses := make([]gin.H, 0, len(items))
+	for i, item := range items {
+		itemResponses = append(itemResponses, gin.H{
+			"position": item.Position,
+			"added_at": item.CreatedAt,
+			"post":     postResponses[i],
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"id":          list.ID,
+			"name":        list.Name,
+			"description": list.Description,
+			"public":      list.Public,
+			"is_default":  list.IsDefault,
+			"user":        models.ToUserResponse(list.User),
+			"items":       itemResponses,
+			"created_at":  list.CreatedAt,
+			"updated_at":  list.UpdatedAt,
+		},
+	})
+}
+
+func AddReadingListItem(c *gin.Context) {
+	list, ok := findOwnReadingList(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		PostID uint `json:"post_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.First(&post, request.PostID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	item, err := services.AddToReadingList(list.ID, post.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not add post to reading list"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Post added to reading list", "data": item})
+}
+
+func RemoveReadingListItem(c *gin.Context) {
+	list, ok := findOwnReadingList(c)
+	if !ok {
+		return
+	}
+
+	postID, ok := parseIDParam(c, "postId", "post")
+	if !ok {
+		return
+	}
+
+	if err := config.DB.Where("reading_list_id = ? AND post_id = ?", list.ID, postID).
+		Delete(&models.ReadingListItem{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not remove post from reading list"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Post removed from reading list"})
+}
+
+// ReorderReadingList takes the complete list of post IDs in their new order.
+func ReorderReadingList(c *gin.Context) {
+	list, ok := findOwnReadingList(c)
+	if !ok {
+		return
+	}
+
+	if !reorderPosts(c, orderedPosts{
+		name:  "reading list",
+		model: &models.ReadingListItem{},
+		scope: func(db *gorm.DB) *gorm.DB {
+			return db.Where("reading_list_id = ?", list.ID)
+		},
+		postColumn:     "post_id",
+		positionColumn: "position",
+	}) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reading list reordered successfully"})
+}
+
+// BookmarkPost saves the post to the user's default reading list.
+func BookmarkPost(c *gin.Context) {
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.First(&post, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	list, err := services.DefaultReadingList(c.MustGet("userID").(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not bookmark post"})
+		return
+	}
+
+	if _, err := services.AddToReadingList(list.ID, post.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not bookmark post"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Post bookmarked", "data": gin.H{"bookmarked": true, "reading_list_id": list.ID}})
+}
+
+// UnbookmarkPost removes the post from every reading list of the user.
+func UnbookmarkPost(c *gin.Context) {
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return
+	}
+
+	userID := c.MustGet("userID").(uint)
+
+	ownLists := config.DB.Model(&models.ReadingList{}).Select("id").Where("user_id = ?", userID)
+	if err := config.DB.Where("post_id = ? AND reading_list_id IN (?)", postID, ownLists).
+		Delete(&models.ReadingListItem{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not remove bookmark"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Bookmark removed", "data": gin.H{"bookmarked": false}})
+}
+
+func findOwnReadingList(c *gin.Context) (models.ReadingList, bool) {
+	var list models.ReadingList
+
+	listID, ok := parseIDParam(c, "id", "reading list")
+	if !ok {
+		return list, false
+	}
+
+	if err := config.DB.First(&list, listID).Error; err != nil || list.UserID != c.MustGet("userID").(uint) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reading list not found"})
+		return list, false
+	}
+
+	return list, true
+}
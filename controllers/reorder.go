@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// orderedPosts describes a collection whose posts the owner can reorder: the
+// rows of model selected by scope, identified by postColumn and ordered by
+// positionColumn. name is used in error messages ("series", "reading list").
+type orderedPosts struct {
+	name           string
+	model          interface{}
+	scope          func(*gorm.DB) *gorm.DB
+	postColumn     string
+	positionColumn string
+}
+
+// reorderPosts binds {"post_ids": [...]}, checks that it lists every post of
+// the collection exactly once and rewrites the positions in that order. On
+// failure it writes the error response and returns false.
+func reorderPosts(c *gin.Context, collection orderedPosts) bool {
+	var request struct {
+		PostIDs []uint `json:"post_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	var postIDs []uint
+	if err := config.DB.Model(collection.model).
+		Scopes(collection.scope).
+		Pluck(collection.postColumn, &postIDs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load " + collection.name + " posts"})
+		return false
+	}
+
+	if !isPermutation(request.PostIDs, postIDs) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "post_ids must list every post in the " + collection.name + " exactly once"})
+		return false
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for position, postID := range request.PostIDs {
+			if err := tx.Model(collection.model).
+				Scopes(collection.scope).
+				Where(collection.postColumn+" = ?", postID).
+				Update(collection.positionColumn, position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not reorder " + collection.name})
+		return false
+	}
+
+	return true
+}
+
+// isPermutation reports whether ids holds exactly the values of current, each
+// once, in any order.
+func isPermutation(ids []uint, current []uint) bool {
+	if len(ids) != len(current) {
+		return false
+	}
+
+	remaining := make(map[uint]bool, len(current))
+	for _, id := range current {
+		remaining[id] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return false
+		}
+		delete(remaining, id)
+	}
+	return true
+}
@@ -0,0 +1,234 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/responses"
+	"backend/services"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const sitemapContentType = "application/xml; charset=utf-8"
+
+// sitemapSection is one kind of page listed in the sitemap. Sections are laid
+// out one after another, so a sitemap page can span the end of one and the
+// start of the next.
+type sitemapSection struct {
+	query func(site string) *gorm.DB
+	load  func(query *gorm.DB, site string) ([]services.SitemapURL, error)
+}
+
+var sitemapSections = []sitemapSection{
+	{
+		// Posts whose canonical URL points to another site are left to that site.
+		query: func(site string) *gorm.DB {
+			return config.DB.Model(&models.Post{}).
+				Where("canonical_url IS NULL OR canonical_url = '' OR canonical_url LIKE ?", site+"/%")
+		},
+		load: func(query *gorm.DB, site string) ([]services.SitemapURL, error) {
+			var posts []models.Post
+			if err := query.Select("id", "updated_at").Order("id ASC").Find(&posts).Error; err != nil {
+				return nil, err
+			}
+			entries := make([]services.SitemapURL, 0, len(posts))
+			for _, post := range posts {
+				entries = append(entries, services.SitemapURL{Location: services.PostURL(site, post.ID), LastModified: post.UpdatedAt})
+			}
+			return entries, nil
+		},
+	},
+	{
+		query: func(string) *gorm.DB {
+			return config.DB.Model(&models.Tag{}).
+				Where("EXISTS (SELECT 1 FROM post_tags WHERE post_tags.tag_id = tags.id)")
+		},
+		load: func(query *gorm.DB, site string) ([]services.SitemapURL, error) {
+			var tags []models.Tag
+			if err := query.Select("id", "name").Order("id ASC").Find(&tags).Error; err != nil {
+				return nil, err
+			}
+			entries := make([]services.SitemapURL, 0, len(tags))
+			for _, tag := range tags {
+				entries = append(entries, services.SitemapURL{Location: site + "/tags/" + url.PathEscape(tag.Name)})
+			}
+			return entries, nil
+		},
+	},
+	{
+		// Only authors; profiles without posts have nothing worth indexing.
+		query: func(string) *gorm.DB {
+			return config.DB.Model(&models.User{}).
+				Where("EXISTS (SELECT 1 FROM posts WHERE posts.user_id = users.id)")
+		},
+		load: func(query *gorm.DB, site string) ([]services.SitemapURL, error) {
+			var users []models.User
+			if err := query.Select("id", "updated_at").Order("id ASC").Find(&users).Error; err != nil {
+				return nil, err
+			}
+			entries := make([]services.SitemapURL, 0, len(users))
+			for _, user := range users {
+				entries = append(entries, services.SitemapURL{Location: fmt.Sprintf("%s/users/%d", site, user.ID), LastModified: user.UpdatedAt})
+			}
+			return entries, nil
+		},
+	},
+}
+
+func sitemapCounts(site string) ([]int64, int64, error) {
+	counts := make([]int64, len(sitemapSections))
+	var total int64
+	for i, section := range sitemapSections {
+		if err := section.query(site).Count(&counts[i]).Error; err != nil {
+			return nil, 0, err
+		}
+		total += counts[i]
+	}
+	return counts, total, nil
+}
+
+// sitemapRange is the part of one section that falls on a sitemap page.
+type sitemapRange struct {
+	section       int
+	offset, limit int64
+}
+
+// sitemapRanges lays the sections, with the given sizes, end to end and
+// returns the ranges that make up the 1-based page.
+func sitemapRanges(counts []int64, page int) []sitemapRange {
+	offset := int64(page-1) * services.SitemapMaxURLs
+	remaining := int64(services.SitemapMaxURLs)
+
+	var ranges []sitemapRange
+	for i, count := range counts {
+		if remaining == 0 {
+			break
+		}
+		if offset >= count {
+			offset -= count
+			continue
+		}
+
+		limit := min(count-offset, remaining)
+		ranges = append(ranges, sitemapRange{section: i, offset: offset, limit: limit})
+		remaining -= limit
+		offset = 0
+	}
+	return ranges
+}
+
+// sitemapPage loads the URLs of the 1-based page.
+func sitemapPage(site string, counts []int64, page int) ([]services.SitemapURL, error) {
+	var entries []services.SitemapURL
+	for _, part := range sitemapRanges(counts, page) {
+		section := sitemapSections[part.section]
+		sectionEntries, err := section.load(section.query(site).Offset(int(part.offset)).Limit(int(part.limit)), site)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, sectionEntries...)
+	}
+	return entries, nil
+}
+
+func serveSitemap(c *gin.Context, site string, counts []int64, page int) {
+	entries, err := sitemapPage(site, counts, page)
+	if err != nil {
+		log.Println("Error loading sitemap:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not build sitemap"})
+		return
+	}
+
+	body, err := services.RenderSitemap(entries)
+	if err != nil {
+		log.Println("Error rendering sitemap:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not build sitemap"})
+		return
+	}
+
+	// No Last-Modified: deleting a page changes the sitemap without changing
+	// any remaining entry's date.
+	responses.ConditionalData(c, sitemapContentType, body, time.Time{}, "")
+}
+
+// GetSitemap serves every public page in one sitemap, or a sitemap index of
+// /sitemaps/{n}.xml pages once there are more than SitemapMaxURLs of them.
+func GetSitemap(c *gin.Context) {
+	site := siteURL(c)
+	counts, total, err := sitemapCounts(site)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not build sitemap"})
+		return
+	}
+
+	if total <= services.SitemapMaxURLs {
+		serveSitemap(c, site, counts, 1)
+		return
+	}
+
+	pages := int((total + services.SitemapMaxURLs - 1) / services.SitemapMaxURLs)
+	sitemaps := make([]services.SitemapURL, 0, pages)
+	for page := 1; page <= pages; page++ {
+		sitemaps = append(sitemaps, services.SitemapURL{Location: fmt.Sprintf("%s/sitemaps/%d.xml", site, page)})
+	}
+
+	body, err := services.RenderSitemapIndex(sitemaps)
+	if err != nil {
+		log.Println("Error rendering sitemap index:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not build sitemap"})
+		return
+	}
+
+	responses.ConditionalData(c, sitemapContentType, body, time.Time{}, "")
+}
+
+// GetSitemapPage serves one page of a split sitemap, /sitemaps/{n}.xml.
+func GetSitemapPage(c *gin.Context) {
+	page, err := strconv.Atoi(strings.TrimSuffix(c.Param("page"), ".xml"))
+	if err != nil || page < 1 || !strings.HasSuffix(c.Param("page"), ".xml") {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+		return
+	}
+
+	site := siteURL(c)
+	counts, total, err := sitemapCounts(site)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not build sitemap"})
+		return
+	}
+	if int64(page-1)*services.SitemapMaxURLs >= total && page > 1 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sitemap not found"})
+		return
+	}
+
+	serveSitemap(c, site, counts, page)
+}
+
+func GetRobotsTxt(c *gin.Context) {
+	c.String(http.StatusOK, services.RobotsTxt(siteURL(c)))
+}
+
+// GetPostSEO returns the resolved title, description, canonical URL and
+// Open Graph data of a post, for server-side rendering of its <head>.
+func GetPostSEO(c *gin.Context) {
+	postID, ok := parseIDParam(c, "id", "post")
+	if !ok {
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.Preload("Tags").First(&post, postID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	responses.ConditionalJSON(c, gin.H{"data": services.PostSEO(post, siteURL(c))}, post.UpdatedAt, "")
+}
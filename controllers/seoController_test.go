@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/services"
+	"reflect"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestSitemapRanges(t *testing.T) {
+	const full = services.SitemapMaxURLs
+
+	for _, test := range []struct {
+		name   string
+		counts []int64
+		page   int
+		want   []sitemapRange
+	}{
+		{"empty site", []int64{0, 0, 0}, 1, nil},
+		{"everything on one page", []int64{10, 3, 2}, 1, []sitemapRange{{0, 0, 10}, {1, 0, 3}, {2, 0, 2}}},
+		{"empty section skipped", []int64{10, 0, 2}, 1, []sitemapRange{{0, 0, 10}, {2, 0, 2}}},
+		{"exactly one full page", []int64{full - 5, 3, 2}, 1, []sitemapRange{{0, 0, full - 5}, {1, 0, 3}, {2, 0, 2}}},
+		{"nothing past a full page", []int64{full - 5, 3, 2}, 2, nil},
+		{"first section split", []int64{full + 7, 3, 2}, 1, []sitemapRange{{0, 0, full}}},
+		{"rest of the first section", []int64{full + 7, 3, 2}, 2, []sitemapRange{{0, full, 7}, {1, 0, 3}, {2, 0, 2}}},
+		{"page ends inside the second section", []int64{full - 1, 5, 2}, 1, []sitemapRange{{0, 0, full - 1}, {1, 0, 1}}},
+		{"page starts inside the second section", []int64{full - 1, 5, 2}, 2, []sitemapRange{{1, 1, 4}, {2, 0, 2}}},
+		{"page starts at a section boundary", []int64{full, 5, 2}, 2, []sitemapRange{{1, 0, 5}, {2, 0, 2}}},
+		{"section spanning whole pages", []int64{2, 2*full + 3, 4}, 2, []sitemapRange{{1, full - 2, full}}},
+		{"last page of a long section", []int64{2, 2*full + 3, 4}, 3, []sitemapRange{{1, 2*full - 2, 5}, {2, 0, 4}}},
+		{"page past the end", []int64{2, 2*full + 3, 4}, 4, nil},
+	} {
+		got := sitemapRanges(test.counts, test.page)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: page %d = %+v, want %+v", test.name, test.page, got, test.want)
+		}
+
+		var listed int64
+		for _, part := range got {
+			listed += part.limit
+		}
+		if listed > full {
+			t.Errorf("%s: page %d lists %d URLs, more than %d", test.name, test.page, listed, full)
+		}
+	}
+}
+
+func TestSitemapPageListsSectionsInOrder(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.Post{}, &models.Tag{}); err != nil {
+		t.Fatal(err)
+	}
+	previous := config.DB
+	config.DB = db
+	t.Cleanup(func() {
+		config.DB = previous
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	const site = "https://blog.example"
+	author := models.User{Name: "Author", Username: "author", Password: "x", RoleID: 2}
+	reader := models.User{Name: "Reader", Username: "reader", Password: "x", RoleID: 2}
+	for _, user := range []*models.User{&author, &reader} {
+		if err := db.Create(user).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Create(&models.Tag{Name: "unused"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, post := range []models.Post{
+		{Title: "One", Content: "x", UserID: author.ID, Tags: []models.Tag{{Name: "go"}}},
+		{Title: "Elsewhere", Content: "x", UserID: author.ID, CanonicalURL: "https://other.example/one"},
+		{Title: "Two", Content: "x", UserID: author.ID, CanonicalURL: site + "/posts/3", Tags: []models.Tag{{Name: "web dev"}}},
+	} {
+		if err := db.Create(&post).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	counts, total, err := sitemapCounts(site)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(counts, []int64{2, 2, 1}) || total != 5 {
+		t.Fatalf("counts = %v, total %d, want [2 2 1] and 5", counts, total)
+	}
+
+	entries, err := sitemapPage(site, counts, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Location)
+	}
+	want := []string{
+		services.PostURL(site, 1),
+		services.PostURL(site, 3),
+		site + "/tags/go",
+		site + "/tags/web%20dev",
+		site + "/users/1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("page 1 = %q, want %q", got, want)
+	}
+}
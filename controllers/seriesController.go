@@ -0,0 +1,288 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type seriesRequest struct {
+	Title       string `json:"title" binding:"required,max=200"`
+	Slug        string `json:"slug" binding:"max=100"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+func toSeriesResponse(series models.Series) gin.H {
+	return gin.H{
+		"id":          series.ID,
+		"title":       series.Title,
+		"slug":        series.Slug,
+		"description": series.Description,
+		"user":        models.ToUserResponse(series.User),
+		"created_at":  series.CreatedAt,
+		"updated_at":  series.UpdatedAt,
+	}
+}
+
+// GetSeries returns the series with its posts in reading order.
+func GetSeries(c *gin.Context) {
+	var series models.Series
+	if err := config.DB.Preload("User").Where("slug = ?", c.Param("slug")).First(&series).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		return
+	}
+
+	var posts []models.Post
+	if err := config.DB.Scopes(services.PreloadAuthors).
+		Preload("Tags").
+		Where("series_id = ?", series.ID).
+		Order(services.SeriesPostOrder).
+		Find(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve series posts"})
+		return
+	}
+
+	postResponses := toPostResponses(posts)
+	for i := range postResponses {
+		postResponses[i]["part"] = i + 1
+	}
+	applyViewerState(c, postResponses...)
+
+	seriesResponse := toSeriesResponse(series)
+	seriesResponse["posts"] = postResponses
+	seriesResponse["total"] = len(posts)
+
+	c.JSON(http.StatusOK, gin.H{"data": seriesResponse})
+}
+
+func GetMySeries(c *gin.Context) {
+	var series []models.Series
+	if err := config.DB.Preload("User").
+		Where("user_id = ?", c.MustGet("userID").(uint)).
+		Order("created_at DESC").
+		Find(&series).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve series"})
+		return
+	}
+
+	var response []gin.H
+	for _, item := range series {
+		response = append(response, toSeriesResponse(item))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": response})
+}
+
+func CreateSeries(c *gin.Context) {
+	var request seriesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slug := services.Slugify(request.Slug)
+	if slug == "" {
+		slug = services.Slugify(request.Title)
+	}
+	if slug == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A slug with letters or digits is required"})
+		return
+	}
+
+	var existing int64
+	if err := config.DB.Model(&models.Series{}).Where("slug = ?", slug).Count(&existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create series"})
+		return
+	}
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Slug is already taken"})
+		return
+	}
+
+	series := models.Series{
+		UserID:      c.MustGet("userID").(uint),
+		Title:       request.Title,
+		Slug:        slug,
+		Description: request.Description,
+	}
+	if err := config.DB.Create(&series).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Series created successfully", "data": series})
+}
+
+// UpdateSeries changes the title and description; the slug is kept so
+// existing links keep working.
+func UpdateSeries(c *gin.Context) {
+	series, ok := findOwnSeries(c)
+	if !ok {
+		return
+	}
+
+	var request seriesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	series.Title = request.Title
+	series.Description = request.Description
+
+	if err := config.DB.Save(&series).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Series updated successfully", "data": series})
+}
+
+// DeleteSeries removes the series; its posts stay published as standalone
+// posts.
+func DeleteSeries(c *gin.Context) {
+	series, ok := findOwnSeries(c)
+	if !ok {
+		return
+	}
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Post{}).
+			Where("series_id = ?", series.ID).
+			Updates(map[string]interface{}{"series_id": nil, "series_position": 0}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&series).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Series deleted successfully"})
+}
+
+// AddSeriesPost appends one of the author's posts to the end of the series,
+// moving it out of any series it was in before.
+func AddSeriesPost(c *gin.Context) {
+	series, ok := findOwnSeries(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		PostID uint `json:"post_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var post models.Post
+	if err := config.DB.First(&post, request.PostID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		return
+	}
+
+	role, err := services.PostRole(post, series.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check permissions"})
+		return
+	}
+	if !services.RoleAtLeast(role, models.CollaboratorOwner) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only your own posts can be added to a series"})
+		return
+	}
+
+	var last struct {
+		Position *int
+	}
+	if err := config.DB.Model(&models.Post{}).
+		Select("MAX(series_position) AS position").
+		Where("series_id = ? AND id <> ?", series.ID, post.ID).
+		Scan(&last).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not add post to series"})
+		return
+	}
+
+	position := 0
+	if last.Position != nil {
+		position = *last.Position + 1
+	}
+
+	if err := config.DB.Model(&post).Updates(map[string]interface{}{
+		"series_id":       series.ID,
+		"series_position": position,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not add post to series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Post added to series", "data": gin.H{
+		"post_id":         post.ID,
+		"series_id":       series.ID,
+		"series_position": position,
+	}})
+}
+
+func RemoveSeriesPost(c *gin.Context) {
+	series, ok := findOwnSeries(c)
+	if !ok {
+		return
+	}
+
+	postID, ok := parseIDParam(c, "postId", "post")
+	if !ok {
+		return
+	}
+
+	if err := config.DB.Model(&models.Post{}).
+		Where("id = ? AND series_id = ?", postID, series.ID).
+		Updates(map[string]interface{}{"series_id": nil, "series_position": 0}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not remove post from series"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Post removed from series"})
+}
+
+// ReorderSeries takes the complete list of post IDs in their new order.
+func ReorderSeries(c *gin.Context) {
+	series, ok := findOwnSeries(c)
+	if !ok {
+		return
+	}
+
+	if !reorderPosts(c, orderedPosts{
+		name:  "series",
+		model: &models.Post{},
+		scope: func(db *gorm.DB) *gorm.DB {
+			return db.Where("series_id = ?", series.ID)
+		},
+		postColumn:     "id",
+		positionColumn: "series_position",
+	}) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Series reordered successfully"})
+}
+
+func findOwnSeries(c *gin.Context) (models.Series, bool) {
+	var series models.Series
+	if err := config.DB.Where("slug = ?", c.Param("slug")).First(&series).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		return series, false
+	}
+
+	if series.UserID != c.MustGet("userID").(uint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to manage this series"})
+		return series, false
+	}
+
+	return series, true
+}
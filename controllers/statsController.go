@@ -0,0 +1,288 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/services"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	statsDefaultDays = 30
+	statsMaxDays     = 366
+	statsTopLimit    = 10
+)
+
+type statsTotals struct {
+	Views    int64 `json:"views"`
+	Reads    int64 `json:"reads"`
+	Claps    int64 `json:"claps"`
+	Comments int64 `json:"comments"`
+}
+
+// statsRow is what the rollup queries scan into; gorm does not fill
+// unexported embedded structs, so the counters are spelled out.
+type statsRow struct {
+	Day      string
+	PostID   uint
+	Title    string
+	Views    int64 `gorm:"column:view_count"`
+	Reads    int64 `gorm:"column:read_count"`
+	Claps    int64 `gorm:"column:clap_count"`
+	Comments int64 `gorm:"column:comment_count"`
+}
+
+func (row statsRow) totals() statsTotals {
+	return statsTotals{Views: row.Views, Reads: row.Reads, Claps: row.Claps, Comments: row.Comments}
+}
+
+type statsDay struct {
+	Date string `json:"date"`
+	statsTotals
+	NewFollowers int64 `json:"new_followers"`
+}
+
+// GetMyStats is the author dashboard: a daily series of views, reads, claps,
+// comments and new followers, per-post totals and top referrers over
+// ?from/?to (YYYY-MM-DD, default the last 30 days). ?post_id narrows it to one
+// post and ?format=csv exports the per-post daily rows. Figures come from the
+// rollups, so today's are refreshed every few minutes.
+func GetMyStats(c *gin.Context) {
+	userID := c.MustGet("userID").(uint)
+
+	from, to, ok := statsRange(c)
+	if !ok {
+		return
+	}
+
+	query := config.DB.Model(&models.PostDailyStat{}).
+		Where("post_daily_stats.author_id = ? AND post_daily_stats.day >= ? AND post_daily_stats.day <= ?", userID, from, to)
+	referrerQuery := config.DB.Model(&models.ReferrerDailyStat{}).
+		Where("author_id = ? AND day >= ? AND day <= ?", userID, from, to)
+
+	if value := c.Query("post_id"); value != "" {
+		postID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post_id"})
+			return
+		}
+
+		var post models.Post
+		if err := config.DB.Where("id = ? AND user_id = ?", postID, userID).First(&post).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			return
+		}
+
+		query = query.Where("post_daily_stats.post_id = ?", post.ID)
+		referrerQuery = referrerQuery.Where("post_id = ?", post.ID)
+	}
+
+	if c.Query("format") == "csv" {
+		exportStatsCSV(c, query, from, to)
+		return
+	}
+
+	var dailyRows []statsRow
+	if err := query.Session(&gorm.Session{}).
+		Select("day, SUM(view_count) AS view_count, SUM(read_count) AS read_count, " +
+			"SUM(clap_count) AS clap_count, SUM(comment_count) AS comment_count").
+		Group("day").
+		Scan(&dailyRows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stats"})
+		return
+	}
+
+	var followerRows []models.AuthorDailyStat
+	if err := config.DB.Where("author_id = ? AND day >= ? AND day <= ?", userID, from, to).
+		Find(&followerRows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stats"})
+		return
+	}
+
+	days := make(map[string]*statsDay)
+	series := make([]*statsDay, 0, int(to.Sub(from).Hours()/24)+1)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		entry := &statsDay{Date: day.Format("2006-01-02")}
+		days[entry.Date] = entry
+		series = append(series, entry)
+	}
+
+	var totals statsDay
+	for _, row := range dailyRows {
+		day, err := services.ParseStatsDay(row.Day)
+		if err != nil {
+			log.Println("Skipping stats row:", err)
+			continue
+		}
+		if entry := days[day.Format("2006-01-02")]; entry != nil {
+			entry.statsTotals = row.totals()
+		}
+		totals.Views += row.Views
+		totals.Reads += row.Reads
+		totals.Claps += row.Claps
+		totals.Comments += row.Comments
+	}
+	for _, row := range followerRows {
+		if entry := days[row.Day.UTC().Format("2006-01-02")]; entry != nil {
+			entry.NewFollowers = row.NewFollowers
+		}
+		totals.NewFollowers += row.NewFollowers
+	}
+
+	var postRows []statsRow
+	if err := query.Session(&gorm.Session{}).
+		Select("post_daily_stats.post_id, posts.title, SUM(post_daily_stats.view_count) AS view_count, " +
+			"SUM(post_daily_stats.read_count) AS read_count, SUM(post_daily_stats.clap_count) AS clap_count, " +
+			"SUM(post_daily_stats.comment_count) AS comment_count").
+		Joins("JOIN posts ON posts.id = post_daily_stats.post_id").
+		Group("post_daily_stats.post_id, posts.title").
+		Order("view_count DESC").
+		Scan(&postRows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stats"})
+		return
+	}
+
+	posts := make([]gin.H, 0, len(postRows))
+	for _, row := range postRows {
+		posts = append(posts, gin.H{
+			"post_id":  row.PostID,
+			"title":    row.Title,
+			"views":    row.Views,
+			"reads":    row.Reads,
+			"claps":    row.Claps,
+			"comments": row.Comments,
+		})
+	}
+
+	var referrers []struct {
+		Host  string `json:"host"`
+		Views int64  `json:"views"`
+	}
+	if err := referrerQuery.
+		Select("host, SUM(views) AS views").
+		Group("host").
+		Order("views DESC").
+		Limit(statsTopLimit).
+		Scan(&referrers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stats"})
+		return
+	}
+
+	readRatio := 0.0
+	if totals.Views > 0 {
+		readRatio = float64(totals.Reads) / float64(totals.Views)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"from": from.Format("2006-01-02"),
+		"to":   to.Format("2006-01-02"),
+		"totals": gin.H{
+			"views":         totals.Views,
+			"reads":         totals.Reads,
+			"claps":         totals.Claps,
+			"comments":      totals.Comments,
+			"new_followers": totals.NewFollowers,
+			"read_ratio":    readRatio,
+		},
+		"series":        series,
+		"posts":         posts,
+		"top_referrers": referrers,
+	}})
+}
+
+// statsRange reads ?from and ?to as whole UTC days, both inclusive.
+func statsRange(c *gin.Context) (time.Time, time.Time, bool) {
+	to := services.StatsDay(time.Now())
+	from := to.AddDate(0, 0, -(statsDefaultDays - 1))
+
+	if value := c.Query("to"); value != "" {
+		t, err := parseStatsDateParam(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date"})
+			return from, to, false
+		}
+		to = services.StatsDay(t)
+		from = to.AddDate(0, 0, -(statsDefaultDays - 1))
+	}
+
+	if value := c.Query("from"); value != "" {
+		t, err := parseStatsDateParam(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date"})
+			return from, to, false
+		}
+		from = services.StatsDay(t)
+	}
+
+	if from.After(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return from, to, false
+	}
+	if to.Sub(from) >= statsMaxDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("The range is limited to %d days", statsMaxDays)})
+		return from, to, false
+	}
+
+	return from, to, true
+}
+
+// parseStatsDateParam is parseDateParam for stats ranges: a plain
+// YYYY-MM-DD date names that UTC day, whatever the server's time zone.
+func parseStatsDateParam(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.UTC)
+}
+
+func exportStatsCSV(c *gin.Context, query *gorm.DB, from, to time.Time) {
+	var rows []statsRow
+	if err := query.
+		Select("post_daily_stats.day, post_daily_stats.post_id, posts.title, post_daily_stats.view_count, " +
+			"post_daily_stats.read_count, post_daily_stats.clap_count, post_daily_stats.comment_count").
+		Joins("JOIN posts ON posts.id = post_daily_stats.post_id").
+		Order("post_daily_stats.day ASC, post_daily_stats.post_id ASC").
+		Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not load stats"})
+		return
+	}
+
+	filename := fmt.Sprintf("stats-%s-%s.csv", from.Format("20060102"), to.Format("20060102"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write([]string{"date", "post_id", "title", "views", "reads", "claps", "comments"})
+
+	for _, row := range rows {
+		day, err := services.ParseStatsDay(row.Day)
+		if err != nil {
+			log.Println("Skipping stats row:", err)
+			continue
+		}
+
+		writer.Write([]string{
+			day.Format("2006-01-02"),
+			strconv.FormatUint(uint64(row.PostID), 10),
+			csvCell(row.Title),
+			strconv.FormatInt(row.Views, 10),
+			strconv.FormatInt(row.Reads, 10),
+			strconv.FormatInt(row.Claps, 10),
+			strconv.FormatInt(row.Comments, 10),
+		})
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Println("Failed to write stats CSV:", err)
+	}
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestStatsRange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Dates must name UTC days even when the server runs east of UTC.
+	previous := time.Local
+	time.Local = time.FixedZone("UTC+7", 7*60*60)
+	t.Cleanup(func() { time.Local = previous })
+
+	day := func(value string) time.Time {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	for _, test := range []struct {
+		query    string
+		from, to time.Time
+		status   int
+	}{
+		{query: "from=2026-03-01&to=2026-03-10", from: day("2026-03-01"), to: day("2026-03-10"), status: http.StatusOK},
+		{query: "to=2026-03-30", from: day("2026-03-01"), to: day("2026-03-30"), status: http.StatusOK},
+		{query: "from=2026-03-10&to=2026-03-10", from: day("2026-03-10"), to: day("2026-03-10"), status: http.StatusOK},
+		{query: "from=2026-03-10T05:00:00%2B07:00&to=2026-03-10T12:00:00Z", from: day("2026-03-09"), to: day("2026-03-10"), status: http.StatusOK},
+		{query: "from=2025-01-01&to=2026-01-01", from: day("2025-01-01"), to: day("2026-01-01"), status: http.StatusOK},
+		{query: "from=2025-01-01&to=2026-01-02", status: http.StatusBadRequest},
+		{query: "from=2026-03-11&to=2026-03-10", status: http.StatusBadRequest},
+		{query: "from=yesterday", status: http.StatusBadRequest},
+		{query: "to=2026-13-01", status: http.StatusBadRequest},
+	} {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest(http.MethodGet, "/stats?"+test.query, nil)
+
+		from, to, ok := statsRange(c)
+		if test.status != http.StatusOK {
+			if ok || recorder.Code != test.status {
+				t.Errorf("%s: ok = %v, status %d, want %d", test.query, ok, recorder.Code, test.status)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%s: rejected with %s", test.query, recorder.Body)
+			continue
+		}
+		if !from.Equal(test.from) || !to.Equal(test.to) {
+			t.Errorf("%s: got %s..%s, want %s..%s", test.query, from, to, test.from, test.to)
+		}
+		if from.Location() != time.UTC || to.Location() != time.UTC {
+			t.Errorf("%s: got days in %s and %s, want UTC", test.query, from.Location(), to.Location())
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package controllers
+
+import (
+	"backend/services"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateStreamTicket exchanges the caller's JWT for a single-use ticket to
+// open an event stream or WebSocket, which cannot carry an Authorization
+// header from a browser.
+func CreateStreamTicket(c *gin.Context) {
+	ticket, err := services.IssueStreamTicket(c.MustGet("userID").(uint))
+	if err != nil {
+		log.Println("Error issuing stream ticket:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not issue stream ticket"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Stream ticket issued", "data": gin.H{
+		"ticket":     ticket,
+		"expires_in": int(services.StreamTicketTTL.Seconds()),
+	}})
+}
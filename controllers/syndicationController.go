@@ -0,0 +1,188 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/responses"
+	"backend/services"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const feedItemLimit = 20
+
+// feedFormat picks the format from the last path segment, so one handler
+// serves feed.xml, atom.xml and feed.json.
+func feedFormat(c *gin.Context) string {
+	switch {
+	case strings.HasSuffix(c.FullPath(), "/atom.xml"):
+		return services.FeedAtom
+	case strings.HasSuffix(c.FullPath(), "/feed.json"):
+		return services.FeedJSON
+	}
+	return services.FeedRSS
+}
+
+func GetSiteFeed(c *gin.Context) {
+	serveFeed(c, "All posts", "The latest posts", "", config.DB.Model(&models.Post{}))
+}
+
+func GetAuthorFeed(c *gin.Context) {
+	userID, ok := parseIDParam(c, "id", "user")
+	if !ok {
+		return
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	serveFeed(c, user.Name, "Posts by "+user.Name, fmt.Sprintf("/users/%d", user.ID),
+		config.DB.Model(&models.Post{}).Where("posts.user_id = ?", user.ID))
+}
+
+func GetTagFeed(c *gin.Context) {
+	var tag models.Tag
+	if err := config.DB.Where("name = ?", c.Param("name")).First(&tag).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	serveFeed(c, "#"+tag.Name, "Posts tagged "+tag.Name, "/tags/"+url.PathEscape(tag.Name),
+		config.DB.Model(&models.Post{}).
+			Joins("JOIN post_tags ON post_tags.post_id = posts.id AND post_tags.tag_id = ?", tag.ID))
+}
+
+func GetPublicationFeed(c *gin.Context) {
+	publication, ok := findPublication(c)
+	if !ok {
+		return
+	}
+
+	description := publication.Description
+	if description == "" {
+		description = "Posts published in " + publication.Name
+	}
+	serveFeed(c, publication.Name, description, "/publications/"+publication.Slug,
+		config.DB.Model(&models.Post{}).
+			Where("posts.publication_id = ? AND posts.publication_status = ?", publication.ID, models.PublicationPublished))
+}
+
+// serveFeed renders the latest posts matched by query in the requested format.
+// ?content=excerpt leaves out the full post body.
+func serveFeed(c *gin.Context, title, description, pagePath string, query *gorm.DB) {
+	content := c.DefaultQuery("content", "full")
+	if content != "full" && content != "excerpt" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "content must be full or excerpt"})
+		return
+	}
+
+	var posts []models.Post
+	if err := query.Scopes(services.PreloadAuthors).
+		Preload("Tags").
+		Order("posts.created_at DESC").
+		Limit(feedItemLimit).
+		Find(&posts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve posts"})
+		return
+	}
+
+	site := siteURL(c)
+	feedURL := site + c.Request.URL.EscapedPath()
+	if content != "full" {
+		feedURL += "?content=" + content
+	}
+	feed := services.SyndicationFeed{
+		Title:       title,
+		Description: description,
+		Link:        site + pagePath,
+		FeedURL:     feedURL,
+		Updated:     latestUpdate(posts),
+	}
+
+	imageSizes := uploadSizes(posts)
+	for _, post := range posts {
+		link := services.PostURL(site, post.ID)
+		item := services.SyndicationItem{
+			ID:        link,
+			Title:     post.Title,
+			Link:      link,
+			Summary:   post.Excerpt,
+			Published: post.CreatedAt,
+			Updated:   post.UpdatedAt,
+		}
+		if content == "full" {
+			item.Content = post.ContentHTML
+		}
+		for _, author := range postAuthors(post) {
+			item.Authors = append(item.Authors, services.SyndicationAuthor{
+				Name: author.Name,
+				URL:  fmt.Sprintf("%s/users/%d", site, author.ID),
+			})
+		}
+		for _, tag := range post.Tags {
+			item.Categories = append(item.Categories, tag.Name)
+		}
+		if post.Image != "" {
+			item.Image = services.ImageEnclosure(post.Image, imageSizes[post.Image])
+		}
+		feed.Items = append(feed.Items, item)
+	}
+
+	format := feedFormat(c)
+	body, err := feed.Render(format)
+	if err != nil {
+		log.Println("Error rendering feed:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not render feed"})
+		return
+	}
+
+	// No Last-Modified: a post dropping out of the feed changes it without
+	// changing any post's timestamp, so only the ETag tells.
+	responses.ConditionalData(c, services.FeedContentTypes[format], body, time.Time{}, "")
+}
+
+// siteURL is services.SiteURL for a response. Without SITE_URL the links
+// depend on the request's Host and X-Forwarded-Proto, so shared caches must
+// not serve the response for another value of either.
+func siteURL(c *gin.Context) string {
+	if site := services.ConfiguredSiteURL(); site != "" {
+		return site
+	}
+	c.Writer.Header().Add("Vary", "Host, X-Forwarded-Proto")
+	return services.RequestOrigin(c.Request)
+}
+
+// uploadSizes looks up the size of post images that were uploaded here, for
+// the enclosure length. Images hosted elsewhere are left out.
+func uploadSizes(posts []models.Post) map[string]int64 {
+	sizes := make(map[string]int64)
+	var images []string
+	for _, post := range posts {
+		if post.Image != "" {
+			images = append(images, post.Image)
+		}
+	}
+	if len(images) == 0 {
+		return sizes
+	}
+
+	var uploads []models.Upload
+	if err := config.DB.Select("url", "size").Where("url IN ?", images).Find(&uploads).Error; err != nil {
+		log.Println("Could not load image sizes:", err)
+		return sizes
+	}
+	for _, upload := range uploads {
+		sizes[upload.URL] = upload.Size
+	}
+	return sizes
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"backend/responses"
+	"backend/services"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetTrendingPosts lists posts by their materialized trending score.
+// ?window=day|week|month (default week) and ?tag= narrow the ranking.
+func GetTrendingPosts(c *gin.Context) {
+	respondTrending(c, c.Query("tag"))
+}
+
+// GetTagTrendingPosts is GetTrendingPosts for a single tag.
+func GetTagTrendingPosts(c *gin.Context) {
+	respondTrending(c, c.Param("name"))
+}
+
+func respondTrending(c *gin.Context, tag string) {
+	window := c.DefaultQuery("window", "week")
+	if _, ok := services.TrendingWindows[window]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "window must be one of day, week or month"})
+		return
+	}
+
+	pageNum, perPageNum, ok := parsePagination(c)
+	if !ok {
+		return
+	}
+
+	key := fmt.Sprintf("%swindow=%s:tag=%s:page=%d:per_page=%d", services.TrendingCachePrefix, window, tag, pageNum, perPageNum)
+	page, err := cachedPostPage(c, key, func() ([]models.Post, int64, error) {
+		query := config.DB.Model(&models.Post{}).
+			Joins("JOIN trending_scores ON trending_scores.post_id = posts.id AND trending_scores.time_window = ?", window)
+		if tag != "" {
+			query = query.
+				Joins("JOIN post_tags ON post_tags.post_id = posts.id").
+				Joins("JOIN tags ON tags.id = post_tags.tag_id AND tags.name = ?", tag)
+		}
+
+		var total int64
+		if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+
+		var posts []models.Post
+		err := query.Scopes(services.PreloadAuthors).
+			Preload("Tags").
+			Order("trending_scores.score DESC, posts.id DESC").
+			Limit(perPageNum).
+			Offset((pageNum - 1) * perPageNum).
+			Find(&posts).Error
+		return posts, total, err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve trending posts"})
+		return
+	}
+
+	applyViewerState(c, page.Data...)
+
+	responses.ConditionalJSON(c, responses.PaginateBody(page.Data, page.Count, pageNum, perPageNum), time.Time{}, "")
+}
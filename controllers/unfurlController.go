@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"backend/services"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UnfurlLink returns a preview (title, description, image and, for known
+// providers, embed markup) for ?url=.
+func UnfurlLink(c *gin.Context) {
+	rawURL := c.Query("url")
+	if rawURL == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url is required"})
+		return
+	}
+
+	preview, err := services.LinkUnfurler.Unfurl(c.Request.Context(), rawURL)
+	switch {
+	case errors.Is(err, services.ErrInvalidUnfurlURL):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrUnfurlBlocked):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "URL is not allowed"})
+		return
+	case errors.Is(err, services.ErrUnfurlFailed):
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Could not fetch a preview for this URL"})
+		return
+	case err != nil:
+		log.Println("Error unfurling link:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not unfurl URL"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": preview})
+}
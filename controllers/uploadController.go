@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	config "backend/configs"
+	"backend/models"
 	"bytes"
 	"context"
 	"encoding/base64"
@@ -126,7 +128,8 @@ func (fs *FirebaseStorage) UploadImage(c *gin.Context) {
 
 	// Create a new writer for the object
 	wc := object.NewWriter(ctx)
-	if _, err := io.Copy(wc, resizedFile); err != nil {
+	size, err := io.Copy(wc, resizedFile)
+	if err != nil {
 		log.Println("Failed to copy resized image to Firebase Storage writer:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload resized image"})
 		return
@@ -155,6 +158,21 @@ func (fs *FirebaseStorage) UploadImage(c *gin.Context) {
 
 	log.Printf("Public URL of uploaded image: %s", imageURL)
 
+	// Keep track of the upload for storage reporting
+	upload := models.Upload{
+		ObjectName:  objectName,
+		URL:         imageURL,
+		ContentType: "image/jpeg",
+		Size:        size,
+	}
+	if userID, ok := c.Get("userID"); ok {
+		id := userID.(uint)
+		upload.UserID = &id
+	}
+	if err := config.DB.Create(&upload).Error; err != nil {
+		log.Println("Failed to record upload:", err)
+	}
+
 	// Respond with the URL
 	c.JSON(http.StatusOK, gin.H{
 		"uploaded": 1,
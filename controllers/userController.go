@@ -212,12 +212,16 @@ func UpdateUserRole(c *gin.Context) {
 		return
 	}
 
+	before := models.ToUserResponse(user)
+
 	user.Role = request.Role
 	if err := config.DB.Save(&user).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user role"})
 		return
 	}
 
+	services.SetAuditTarget(c, "user", user.ID, before, models.ToUserResponse(user))
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "User role updated successfully",
 		"user":    user,
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"backend/cache"
 	config "backend/configs"
 	"backend/models"
 	"backend/routes"
+	"backend/services"
+	"context"
 	"log"
 	"os"
 
@@ -26,7 +29,35 @@ func main() {
 
 	config.ConnectDatabase()
 
-	config.DB.AutoMigrate(&models.User{}, &models.Post{}, &models.Clap{})
+	config.DB.AutoMigrate(models.All()...)
+
+	if err := services.BackfillRenderedContent(); err != nil {
+		log.Println("Failed to render existing posts:", err)
+	}
+
+	if err := services.SetupUnfurler(); err != nil {
+		log.Fatalf("Failed to set up link unfurler: %v", err)
+	}
+
+	if err := cache.Setup(); err != nil {
+		log.Fatalf("Failed to set up cache: %v", err)
+	}
+
+	if err := services.StartEventHub(context.Background()); err != nil {
+		log.Fatalf("Failed to start event hub: %v", err)
+	}
+
+	if err := services.StartActivityRecorder(context.Background()); err != nil {
+		log.Fatalf("Failed to start activity recorder: %v", err)
+	}
+
+	if err := services.StartTrendingJob(context.Background()); err != nil {
+		log.Fatalf("Failed to start trending job: %v", err)
+	}
+
+	if err := services.StartStatsRollupJob(context.Background()); err != nil {
+		log.Fatalf("Failed to start stats rollup job: %v", err)
+	}
 
 	r := gin.Default()
 
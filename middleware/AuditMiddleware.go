@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"backend/services"
+	"log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Audit records the action for the authenticated user when the wrapped handler
+// succeeds. It must run after AuthMiddleware.
+func Audit(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.IsAborted() || c.Writer.Status() >= 400 {
+			return
+		}
+
+		user, exists := c.Get("user")
+		if !exists {
+			return
+		}
+
+		claims, ok := user.(*Claims)
+		if !ok {
+			return
+		}
+
+		entry := services.AuditEntry{
+			ActorID: claims.UserID,
+			Action:  action,
+			IP:      c.ClientIP(),
+		}
+
+		if target, ok := services.AuditTargetOf(c); ok {
+			entry.TargetType = target.Type
+			entry.TargetID = target.ID
+			entry.Before = target.Before
+			entry.After = target.After
+		}
+
+		if err := services.RecordAudit(entry); err != nil {
+			log.Printf("Failed to record audit entry %q: %v", action, err)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"backend/services"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
@@ -20,33 +22,93 @@ type Claims struct {
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tokenString := c.GetHeader("Authorization")
-		log.Println("Received token:", tokenString)
 
 		if tokenString == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing Authorization header"})
 			return
 		}
 
-		if strings.HasPrefix(tokenString, "Bearer ") {
-			tokenString = tokenString[7:]
+		claims, err := parseToken(tokenString)
+		if err != nil {
+			log.Println("Token invalid:", err)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
 		}
 
-		claims := &Claims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return jwtKey, nil
-		})
+		c.Set("user", claims)
+		c.Set("userID", claims.UserID)
+		c.Next()
+	}
+}
 
-		if err != nil || !token.Valid {
-			log.Println("Token invalid:", err)
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+// OptionalAuthMiddleware identifies the user when a valid token is sent but
+// lets anonymous requests through, for public endpoints that personalize
+// their payload.
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := c.GetHeader("Authorization")
+		if tokenString != "" {
+			if claims, err := parseToken(tokenString); err == nil {
+				c.Set("user", claims)
+				c.Set("userID", claims.UserID)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// StreamAuthMiddleware authenticates streaming routes. Besides the usual
+// Authorization header it accepts ?ticket= from POST /stream-tickets, for
+// EventSource and browser WebSocket clients that cannot set headers. A JWT is
+// never accepted in the URL, where access logs would record it.
+func StreamAuthMiddleware() gin.HandlerFunc {
+	auth := AuthMiddleware()
+
+	return func(c *gin.Context) {
+		ticket := c.Query("ticket")
+		if ticket == "" || c.GetHeader("Authorization") != "" {
+			auth(c)
+			return
+		}
+
+		redeemed, err := services.RedeemStreamTicket(ticket)
+		if errors.Is(err, services.ErrInvalidStreamTicket) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired ticket"})
+			return
+		}
+		if err != nil {
+			log.Println("Error redeeming stream ticket:", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not check ticket"})
 			return
 		}
 
+		claims := &Claims{UserID: redeemed.UserID, Role: redeemed.Role}
 		c.Set("user", claims)
+		c.Set("userID", claims.UserID)
 		c.Next()
 	}
 }
 
+func parseToken(tokenString string) (*Claims, error) {
+	if strings.HasPrefix(tokenString, "Bearer ") {
+		tokenString = tokenString[7:]
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return jwtKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, jwt.NewValidationError("token is not valid", jwt.ValidationErrorSignatureInvalid)
+	}
+
+	return claims, nil
+}
+
 func AdminMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		user, exists := c.Get("user")
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	config "backend/configs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheControl sets the route's Cache-Control header. Responses for signed-in
+// users carry per-user fields (e.g. bookmark state), so they are never stored
+// by shared caches. Run it after OptionalAuthMiddleware.
+func CacheControl(name string, fallback string) gin.HandlerFunc {
+	value := config.CacheControl(name, fallback)
+
+	return func(c *gin.Context) {
+		c.Header("Vary", "Authorization")
+
+		if _, loggedIn := c.Get("user"); loggedIn {
+			c.Header("Cache-Control", "private, no-cache")
+		} else {
+			c.Header("Cache-Control", value)
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrAuditLogImmutable = errors.New("audit log entries are append-only")
+
+type AuditLog struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	ActorID    uint      `gorm:"not null;index" json:"actor_id"`
+	Actor      User      `gorm:"foreignKey:ActorID" json:"-"`
+	Action     string    `gorm:"size:100;not null;index" json:"action"`
+	TargetType string    `gorm:"size:50;index:idx_audit_target" json:"target_type"`
+	TargetID   uint      `gorm:"index:idx_audit_target" json:"target_id"`
+	Before     string    `gorm:"type:text" json:"before"`
+	After      string    `gorm:"type:text" json:"after"`
+	IP         string    `gorm:"size:45" json:"ip"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+// Audit entries are append-only, so updates and deletes are rejected
+func (a *AuditLog) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditLogImmutable
+}
+
+func (a *AuditLog) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditLogImmutable
+}
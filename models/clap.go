@@ -7,7 +7,7 @@ type Clap struct {
 	UserID    uint      `gorm:"not null" json:"user_id"`
 	User      User      `gorm:"foreignKey:UserID" json:"user"`
 	PostID    uint      `gorm:"not null" json:"post_id"`
-	Post      Post      `gorm:"foreignKey:PostID" json:"post"`
+	Post      Post      `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE" json:"post"`
 	Count     int       `gorm:"default:0" json:"count"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
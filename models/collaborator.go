@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Roles a user can hold on a post. The post's UserID is always an owner.
+const (
+	CollaboratorOwner  = "owner"
+	CollaboratorEditor = "editor"
+	CollaboratorViewer = "viewer"
+)
+
+var CollaboratorRoles = []string{CollaboratorOwner, CollaboratorEditor, CollaboratorViewer}
+
+// AuthorRoles are the roles credited as authors in the post payload.
+var AuthorRoles = []string{CollaboratorOwner, CollaboratorEditor}
+
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+)
+
+type PostCollaborator struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	PostID      uint       `gorm:"not null;uniqueIndex:idx_post_collaborator" json:"post_id"`
+	Post        Post       `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE" json:"-"`
+	UserID      uint       `gorm:"not null;uniqueIndex:idx_post_collaborator;index" json:"user_id"`
+	User        User       `gorm:"foreignKey:UserID" json:"-"`
+	Role        string     `gorm:"size:20;not null;default:editor" json:"role"`
+	Status      string     `gorm:"size:20;not null;default:pending;index" json:"status"`
+	InvitedByID uint       `gorm:"not null" json:"invited_by_id"`
+	InvitedBy   User       `gorm:"foreignKey:InvitedByID" json:"-"`
+	RespondedAt *time.Time `json:"responded_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
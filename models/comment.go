@@ -3,10 +3,12 @@ package models
 import "time"
 
 type Comment struct {
-	ID          uint   `gorm:"primaryKey"`
-	CommentText string `gorm:"not null"`
-	UserID      uint   `gorm:"not null"`
-	PostID      uint   `gorm:"not null"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	CommentText string    `gorm:"not null" json:"comment_text"`
+	UserID      uint      `gorm:"not null" json:"user_id"`
+	User        User      `gorm:"foreignKey:UserID" json:"-"`
+	PostID      uint      `gorm:"not null;index" json:"post_id"`
+	ParentID    *uint     `gorm:"index" json:"parent_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
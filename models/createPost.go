@@ -1,10 +1,15 @@
 package models
 
 type CreatePostRequest struct {
-	Title       string   `form:"title" binding:"required"`
-	Description string   `form:"desc" binding:"required"`
-	Content     string   `form:"content" binding:"required"`
-	Image       *string  `form:"image"`
-	Categories  []string `form:"categories,omitempty"`
-	Tags        []string `form:"tags,omitempty"`
+	Title           string   `form:"title" binding:"required"`
+	Description     string   `form:"desc"`
+	Content         string   `form:"content" binding:"required"`
+	Format          string   `form:"content_format"` // markdown, blocks or html (default)
+	Image           *string  `form:"image"`
+	MetaTitle       string   `form:"meta_title"`
+	MetaDescription string   `form:"meta_description"`
+	CanonicalURL    string   `form:"canonical_url"`
+	OGImage         string   `form:"og_image"`
+	Categories      []string `form:"categories,omitempty"`
+	Tags            []string `form:"tags,omitempty"`
 }
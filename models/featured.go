@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// FeaturedSlot is one position in the homepage's featured set, chosen by an
+// admin. Slots are shown by ascending Position until they expire.
+type FeaturedSlot struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Position    int        `gorm:"not null;uniqueIndex" json:"position"`
+	PostID      uint       `gorm:"not null;uniqueIndex" json:"post_id"`
+	Post        Post       `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE" json:"-"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedByID uint       `gorm:"not null" json:"created_by_id"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type Follow struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	FollowerID uint      `gorm:"not null;uniqueIndex:idx_follow_pair" json:"follower_id"`
+	Follower   User      `gorm:"foreignKey:FollowerID" json:"-"`
+	FolloweeID uint      `gorm:"not null;uniqueIndex:idx_follow_pair;index" json:"followee_id"`
+	Followee   User      `gorm:"foreignKey:FolloweeID" json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type TagFollow struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_tag_follow_pair" json:"user_id"`
+	TagID     uint      `gorm:"not null;uniqueIndex:idx_tag_follow_pair;index" json:"tag_id"`
+	Tag       Tag       `gorm:"foreignKey:TagID" json:"tag"`
+	CreatedAt time.Time `json:"created_at"`
+}
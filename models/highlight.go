@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Highlight marks a passage of Post.Content. Offsets count characters (runes),
+// not bytes, and EndOffset is exclusive.
+type Highlight struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
+	User        User      `gorm:"foreignKey:UserID" json:"-"`
+	PostID      uint      `gorm:"not null;index" json:"post_id"`
+	Post        Post      `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE" json:"-"`
+	StartOffset int       `gorm:"not null" json:"start_offset"`
+	EndOffset   int       `gorm:"not null" json:"end_offset"`
+	Quote       string    `gorm:"type:text;not null" json:"quote"`
+	Orphaned    bool      `gorm:"default:false" json:"orphaned"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// LinkPreview caches what services.Unfurler found for a URL. Failed lookups
+// are cached too, with Error set, so a broken link is not fetched on every
+// request.
+type LinkPreview struct {
+	ID          uint      `gorm:"primaryKey" json:"-"`
+	URLHash     string    `gorm:"size:64;not null;uniqueIndex" json:"-"` // sha256 of URL
+	URL         string    `gorm:"type:text;not null" json:"url"`
+	Provider    string    `gorm:"size:50" json:"provider"`
+	Type        string    `gorm:"size:20" json:"type"` // link, video, photo or rich
+	Title       string    `gorm:"size:500" json:"title"`
+	Description string    `gorm:"type:text" json:"description"`
+	Image       string    `gorm:"type:text" json:"image"`
+	SiteName    string    `gorm:"size:200" json:"site_name"`
+	AuthorName  string    `gorm:"size:200" json:"author_name"`
+	HTML        string    `gorm:"type:text" json:"html"` // oEmbed markup from a registered provider
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	Error       string    `gorm:"size:255" json:"-"`
+	FetchedAt   time.Time `gorm:"not null" json:"fetched_at"`
+}
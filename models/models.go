@@ -0,0 +1,15 @@
+package models
+
+// All lists every model in migration order, for AutoMigrate.
+func All() []interface{} {
+	return []interface{}{
+		&User{}, &Post{}, &Clap{}, &AuditLog{},
+		&Follow{}, &TagFollow{}, &ReadingList{}, &ReadingListItem{},
+		&Highlight{}, &Comment{}, &Notification{}, &NotificationPreference{},
+		&PostCollaborator{}, &Publication{}, &PublicationMember{},
+		&Series{}, &FeaturedSlot{},
+		&PostView{}, &PostRead{}, &TrendingScore{},
+		&PostDailyStat{}, &AuthorDailyStat{}, &ReferrerDailyStat{},
+		&Upload{}, &LinkPreview{}, &StreamTicket{},
+	}
+}
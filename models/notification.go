@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+const (
+	NotificationClap      = "clap"
+	NotificationComment   = "comment"
+	NotificationReply     = "reply"
+	NotificationMention   = "mention"
+	NotificationFollow    = "follow"
+	NotificationEditorial = "editorial"
+	NotificationInvite    = "invite"
+)
+
+var NotificationTypes = []string{
+	NotificationClap,
+	NotificationComment,
+	NotificationReply,
+	NotificationMention,
+	NotificationFollow,
+	NotificationEditorial,
+	NotificationInvite,
+}
+
+type Notification struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index:idx_notification_user_read" json:"user_id"`
+	ActorID   uint       `gorm:"not null" json:"actor_id"`
+	Actor     User       `gorm:"foreignKey:ActorID" json:"-"`
+	Type      string     `gorm:"size:30;not null" json:"type"`
+	PostID    *uint      `gorm:"index" json:"post_id"`
+	Post      *Post      `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE" json:"-"`
+	CommentID *uint      `json:"comment_id"`
+	Message   string     `gorm:"size:255" json:"message"`
+	Count     int        `gorm:"default:1" json:"count"`
+	Read      bool       `gorm:"column:is_read;default:false;index:idx_notification_user_read" json:"read"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type NotificationPreference struct {
+	ID      uint   `gorm:"primaryKey" json:"id"`
+	UserID  uint   `gorm:"not null;uniqueIndex:idx_notification_pref" json:"user_id"`
+	Type    string `gorm:"size:30;not null;uniqueIndex:idx_notification_pref" json:"type"`
+	Enabled bool   `gorm:"not null" json:"enabled"`
+}
@@ -2,23 +2,53 @@ package models
 
 import "time"
 
+const (
+	ContentFormatMarkdown = "markdown"
+	ContentFormatHTML     = "html"
+	ContentFormatBlocks   = "blocks" // editor.js JSON, see services.BlockDocument
+)
+
 type Tag struct {
 	ID   uint   `gorm:"primaryKey" json:"id"`
 	Name string `gorm:"unique;not null" json:"name"`
 }
 
 type Post struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Title       string    `gorm:"not null" json:"title"`
-	Description string    `gorm:"not null" json:"description"`
-	Content     string    `gorm:"type:text;not null" json:"content"`
-	Image       string    `gorm:"size:255" json:"image"`
-	Pinned      bool      `gorm:"default:false" json:"pinned"`
-	UserID      uint      `gorm:"not null" json:"user_id"`
-	User        User      `gorm:"foreignKey:UserID" json:"user"`
-	Claps       uint      `gorm:"default:0" json:"claps"`
-	Tags        []Tag     `gorm:"many2many:post_tags;" json:"tags"`
-	Comment     uint      `gorm:"default:0" json:"comment"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                  uint               `gorm:"primaryKey" json:"id"`
+	Title               string             `gorm:"not null" json:"title"`
+	Description         string             `gorm:"not null" json:"description"`
+	Content             string             `gorm:"type:text;not null" json:"content"`
+	ContentFormat       string             `gorm:"size:20;not null;default:html" json:"content_format"`
+	ContentHTML         string             `gorm:"type:text" json:"content_html"` // rendered and sanitized Content
+	WordCount           int                `gorm:"not null;default:0" json:"word_count"`
+	ReadingMinutes      int                `gorm:"not null;default:0" json:"reading_minutes"`
+	Excerpt             string             `gorm:"size:500" json:"excerpt"`       // Description, or the opening text when there is none
+	TableOfContents     string             `gorm:"column:toc;type:text" json:"-"` // JSON, see services.TableOfContents
+	Image               string             `gorm:"size:255" json:"image"`
+	MetaTitle           string             `gorm:"size:120" json:"meta_title"` // SEO overrides, see services.PostSEO
+	MetaDescription     string             `gorm:"size:320" json:"meta_description"`
+	CanonicalURL        string             `gorm:"size:512" json:"canonical_url"`
+	OGImage             string             `gorm:"size:512" json:"og_image"`
+	Pinned              bool               `gorm:"default:false" json:"pinned"`
+	PinOrder            int                `gorm:"not null;default:0" json:"pin_order"`
+	PinnedUntil         *time.Time         `json:"pinned_until"`
+	UserID              uint               `gorm:"not null" json:"user_id"`
+	User                User               `gorm:"foreignKey:UserID" json:"user"`
+	Claps               uint               `gorm:"default:0" json:"claps"`
+	Tags                []Tag              `gorm:"many2many:post_tags;" json:"tags"`
+	Comment             uint               `gorm:"default:0" json:"comment"`
+	Views               uint               `gorm:"column:view_count;default:0" json:"views"`
+	Reads               uint               `gorm:"column:read_count;default:0" json:"reads"`
+	Version             uint               `gorm:"not null;default:1" json:"version"`
+	PublicationID       *uint              `gorm:"index" json:"publication_id"`
+	Publication         *Publication       `gorm:"foreignKey:PublicationID;constraint:OnDelete:SET NULL" json:"-"`
+	PublicationStatus   string             `gorm:"size:20" json:"publication_status"` // "" outside a publication
+	PublicationPinned   bool               `gorm:"default:false" json:"publication_pinned"`
+	PublicationFeatured bool               `gorm:"default:false" json:"publication_featured"`
+	SeriesID            *uint              `gorm:"index" json:"series_id"`
+	Series              *Series            `gorm:"foreignKey:SeriesID;constraint:OnDelete:SET NULL" json:"-"`
+	SeriesPosition      int                `gorm:"not null;default:0" json:"series_position"`
+	Collaborators       []PostCollaborator `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE" json:"-"` // accepted co-authors, see services.PreloadAuthors
+	CreatedAt           time.Time          `json:"created_at"`
+	UpdatedAt           time.Time          `json:"updated_at"`
 }
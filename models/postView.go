@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// PostView is one counted view of a post. Views are deduplicated per visitor
+// before they are stored, see services.ActivityRecorder.
+type PostView struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PostID    uint      `gorm:"not null;index:idx_post_view_post_time" json:"post_id"`
+	VisitorID string    `gorm:"size:64;not null" json:"visitor_id"`
+	UserID    *uint     `json:"user_id"`
+	Referrer  string    `gorm:"size:255" json:"referrer"`
+	CreatedAt time.Time `gorm:"index:idx_post_view_post_time" json:"created_at"`
+}
+
+// PostRead is recorded when a client reports that a visitor actually read a
+// post (scrolled far enough or stayed long enough).
+type PostRead struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	PostID    uint      `gorm:"not null;index:idx_post_read_post_time" json:"post_id"`
+	VisitorID string    `gorm:"size:64;not null" json:"visitor_id"`
+	UserID    *uint     `json:"user_id"`
+	CreatedAt time.Time `gorm:"index:idx_post_read_post_time" json:"created_at"`
+}
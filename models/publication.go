@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+const (
+	PublicationOwner  = "owner"
+	PublicationEditor = "editor"
+	PublicationWriter = "writer"
+)
+
+var PublicationRoles = []string{PublicationOwner, PublicationEditor, PublicationWriter}
+
+// Where a post stands in its publication's editorial flow.
+const (
+	PublicationSubmitted = "submitted"
+	PublicationPublished = "published"
+	PublicationRejected  = "rejected"
+)
+
+type Publication struct {
+	ID          uint                `gorm:"primaryKey" json:"id"`
+	Name        string              `gorm:"size:100;not null" json:"name"`
+	Slug        string              `gorm:"size:100;not null;uniqueIndex" json:"slug"`
+	Logo        string              `gorm:"size:255" json:"logo"`
+	Description string              `gorm:"size:500" json:"description"`
+	Members     []PublicationMember `gorm:"foreignKey:PublicationID;constraint:OnDelete:CASCADE" json:"-"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+type PublicationMember struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	PublicationID uint      `gorm:"not null;uniqueIndex:idx_publication_member" json:"publication_id"`
+	UserID        uint      `gorm:"not null;uniqueIndex:idx_publication_member;index" json:"user_id"`
+	User          User      `gorm:"foreignKey:UserID" json:"-"`
+	Role          string    `gorm:"size:20;not null" json:"role"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const DefaultReadingListName = "Reading list"
+
+type ReadingList struct {
+	ID          uint              `gorm:"primaryKey" json:"id"`
+	UserID      uint              `gorm:"not null;index" json:"user_id"`
+	User        User              `gorm:"foreignKey:UserID" json:"-"`
+	Name        string            `gorm:"size:100;not null" json:"name"`
+	Description string            `gorm:"size:500" json:"description"`
+	Public      bool              `gorm:"default:false" json:"public"`
+	IsDefault   bool              `gorm:"default:false" json:"is_default"`
+	DefaultFor  *uint             `gorm:"uniqueIndex" json:"-"` // UserID on the default list, NULL otherwise: one default per user
+	Items       []ReadingListItem `gorm:"foreignKey:ReadingListID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+type ReadingListItem struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ReadingListID uint      `gorm:"not null;uniqueIndex:idx_reading_list_post" json:"reading_list_id"`
+	PostID        uint      `gorm:"not null;uniqueIndex:idx_reading_list_post;index" json:"post_id"`
+	Post          Post      `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE" json:"-"`
+	Position      int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Series is an ordered, multi-part collection of one author's posts. Posts
+// point at it through Post.SeriesID and are ordered by Post.SeriesPosition.
+type Series struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      uint      `gorm:"not null;index" json:"user_id"`
+	User        User      `gorm:"foreignKey:UserID" json:"-"`
+	Title       string    `gorm:"size:200;not null" json:"title"`
+	Slug        string    `gorm:"size:100;not null;uniqueIndex" json:"slug"`
+	Description string    `gorm:"size:500" json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
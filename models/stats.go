@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Daily rollups behind the author stats dashboard, rebuilt in the background
+// by services.RefreshStatsRollup. Day is the UTC calendar day.
+
+type PostDailyStat struct {
+	ID       uint      `gorm:"primaryKey" json:"id"`
+	PostID   uint      `gorm:"not null;uniqueIndex:idx_post_daily_stat" json:"post_id"`
+	AuthorID uint      `gorm:"not null;index:idx_post_daily_stat_author" json:"author_id"`
+	Day      time.Time `gorm:"type:date;not null;uniqueIndex:idx_post_daily_stat;index:idx_post_daily_stat_author" json:"day"`
+	Views    int64     `gorm:"column:view_count;not null;default:0" json:"views"`
+	Reads    int64     `gorm:"column:read_count;not null;default:0" json:"reads"`
+	Claps    int64     `gorm:"column:clap_count;not null;default:0" json:"claps"`
+	Comments int64     `gorm:"column:comment_count;not null;default:0" json:"comments"`
+}
+
+type AuthorDailyStat struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	AuthorID     uint      `gorm:"not null;uniqueIndex:idx_author_daily_stat" json:"author_id"`
+	Day          time.Time `gorm:"type:date;not null;uniqueIndex:idx_author_daily_stat" json:"day"`
+	NewFollowers int64     `gorm:"not null;default:0" json:"new_followers"`
+}
+
+type ReferrerDailyStat struct {
+	ID       uint      `gorm:"primaryKey" json:"id"`
+	PostID   uint      `gorm:"not null;uniqueIndex:idx_referrer_daily_stat" json:"post_id"`
+	AuthorID uint      `gorm:"not null;index:idx_referrer_daily_stat_author" json:"author_id"`
+	Day      time.Time `gorm:"type:date;not null;uniqueIndex:idx_referrer_daily_stat;index:idx_referrer_daily_stat_author" json:"day"`
+	Host     string    `gorm:"size:191;not null;uniqueIndex:idx_referrer_daily_stat" json:"host"`
+	Views    int64     `gorm:"not null;default:0" json:"views"`
+}
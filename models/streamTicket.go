@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// StreamTicket is a short-lived, single-use credential for streaming routes
+// that cannot send an Authorization header. Only a hash of the ticket is
+// stored.
+type StreamTicket struct {
+	ID        uint      `gorm:"primaryKey"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"`
+	UserID    uint      `gorm:"not null"`
+	Role      string    `gorm:"size:20"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
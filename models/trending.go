@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// TrendingScore is a materialized ranking row, rebuilt periodically for each
+// window ("day", "week", "month") by services.RefreshTrending.
+type TrendingScore struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Window     string    `gorm:"column:time_window;size:10;not null;uniqueIndex:idx_trending_window_post;index:idx_trending_window_score" json:"window"`
+	PostID     uint      `gorm:"not null;uniqueIndex:idx_trending_window_post" json:"post_id"`
+	Post       Post      `gorm:"foreignKey:PostID;constraint:OnDelete:CASCADE" json:"-"`
+	Score      float64   `gorm:"not null;index:idx_trending_window_score" json:"score"`
+	Claps      int64     `json:"claps"`
+	Comments   int64     `json:"comments"`
+	Views      int64     `json:"views"`
+	Bookmarks  int64     `json:"bookmarks"`
+	ComputedAt time.Time `json:"computed_at"`
+}
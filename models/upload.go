@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Upload records a file stored in Firebase Storage, so storage usage can be
+// reported without listing the bucket.
+type Upload struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	UserID      *uint     `gorm:"index" json:"user_id"`
+	ObjectName  string    `gorm:"size:255;not null" json:"object_name"`
+	URL         string    `gorm:"size:512;not null" json:"url"`
+	ContentType string    `gorm:"size:100" json:"content_type"`
+	Size        int64     `gorm:"not null;default:0" json:"size"`
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,78 @@
+package responses
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConditionalJSON writes data as JSON together with ETag and Last-Modified
+// validators, and answers 304 Not Modified when the client's cached copy is
+// still current. The ETag is a hash of the body, optionally prefixed (e.g.
+// with a version) so callers can keep meaning in it. Pass a zero lastModified
+// when no single timestamp covers every change to the response, as with
+// listings that lose items.
+func ConditionalJSON(c *gin.Context, data interface{}, lastModified time.Time, etagPrefix string) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "Could not encode response", err.Error())
+		return
+	}
+
+	ConditionalData(c, "application/json; charset=utf-8", body, lastModified, etagPrefix)
+}
+
+// ConditionalData is ConditionalJSON for an already encoded body.
+func ConditionalData(c *gin.Context, contentType string, body []byte, lastModified time.Time, etagPrefix string) {
+	sum := sha1.Sum(body)
+	hash := hex.EncodeToString(sum[:8])
+	etag := `"` + hash + `"`
+	if etagPrefix != "" {
+		etag = `"` + etagPrefix + "-" + hash + `"`
+	}
+
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// notModified applies RFC 9110 precedence: If-None-Match wins, and
+// If-Modified-Since is only consulted when it is absent.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
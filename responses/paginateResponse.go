@@ -7,11 +7,26 @@ import (
 )
 
 func PaginateResponse(c *gin.Context, data interface{}, count int64, page int, perPage int) {
+	c.JSON(200, PaginateBody(data, count, page, perPage))
+}
+
+// PaginateResponseWithMeta giống PaginateResponse nhưng thêm các field phụ (vd: unread_count)
+func PaginateResponseWithMeta(c *gin.Context, data interface{}, count int64, page int, perPage int, meta gin.H) {
+	body := PaginateBody(data, count, page, perPage)
+	for key, value := range meta {
+		body[key] = value
+	}
+
+	c.JSON(200, body)
+}
+
+// PaginateBody dựng JSON phân trang, dùng khi cần tự ghi response (vd: ConditionalJSON)
+func PaginateBody(data interface{}, count int64, page int, perPage int) gin.H {
 	// Tính số trang
 	totalPages := int(math.Ceil(float64(count) / float64(perPage)))
 
 	// Trả về JSON với phân trang
-	c.JSON(200, gin.H{
+	return gin.H{
 		"data": data,
 		"pagination": gin.H{
 			"count":    count,
@@ -19,5 +34,5 @@ func PaginateResponse(c *gin.Context, data interface{}, count int64, page int, p
 			"pages":    totalPages,
 			"per_page": perPage,
 		},
-	})
+	}
 }
@@ -16,13 +16,44 @@ func SetupRouter(r *gin.Engine) {
 		log.Fatalf("Failed to initialize Firebase Storage: %v", err)
 	}
 
-	r.POST("/upload", firebaseStorage.UploadImage)
+	r.POST("/upload", middleware.OptionalAuthMiddleware(), firebaseStorage.UploadImage)
 	r.POST("/register", controllers.RegisterUser)
 	r.POST("/login", controllers.LoginUser)
 
-	r.GET("/posts", controllers.GetAllPosts)
-	r.GET("/posts/pinned", controllers.GetPinnedPosts)
-	r.GET("/posts/:id", controllers.GetPostByID)
+	r.GET("/users/:id", controllers.GetUserProfile)
+	r.GET("/users/:id/followers", controllers.GetFollowers)
+	r.GET("/users/:id/following", controllers.GetFollowing)
+
+	public := r.Group("/")
+	public.Use(middleware.OptionalAuthMiddleware())
+	{
+		public.GET("/posts", middleware.CacheControl("posts", "public, max-age=60, stale-while-revalidate=300"), controllers.GetAllPosts)
+		public.GET("/posts/pinned", middleware.CacheControl("pinned_posts", "public, max-age=300"), controllers.GetPinnedPosts)
+		public.GET("/posts/featured", middleware.CacheControl("featured_posts", "public, max-age=300"), controllers.GetFeaturedPosts)
+		public.GET("/posts/trending", middleware.CacheControl("trending_posts", "public, max-age=300"), controllers.GetTrendingPosts)
+		public.GET("/tags/:name/trending", middleware.CacheControl("trending_posts", "public, max-age=300"), controllers.GetTagTrendingPosts)
+		public.GET("/posts/:id", middleware.CacheControl("post", "public, max-age=60, stale-while-revalidate=300"), controllers.GetPostByID)
+		public.GET("/posts/:id/content", controllers.GetPostContent)
+		public.GET("/posts/:id/seo", controllers.GetPostSEO)
+		public.GET("/posts/:id/comments", controllers.GetPostComments)
+		public.POST("/posts/:id/read", controllers.ReadPost)
+		public.GET("/lists/:id", controllers.GetReadingList)
+		public.GET("/publications/:slug", controllers.GetPublication)
+		public.GET("/series/:slug", controllers.GetSeries)
+
+		feedCache := middleware.CacheControl("feed", "public, max-age=300")
+		for _, file := range []string{"feed.xml", "atom.xml", "feed.json"} {
+			public.GET("/"+file, feedCache, controllers.GetSiteFeed)
+			public.GET("/users/:id/"+file, feedCache, controllers.GetAuthorFeed)
+			public.GET("/tags/:name/"+file, feedCache, controllers.GetTagFeed)
+			public.GET("/publications/:slug/"+file, feedCache, controllers.GetPublicationFeed)
+		}
+
+		sitemapCache := middleware.CacheControl("sitemap", "public, max-age=3600")
+		public.GET("/sitemap.xml", sitemapCache, controllers.GetSitemap)
+		public.GET("/sitemaps/:page", sitemapCache, controllers.GetSitemapPage)
+		public.GET("/robots.txt", sitemapCache, controllers.GetRobotsTxt)
+	}
 
 	authorized := r.Group("/")
 	authorized.Use(middleware.AuthMiddleware())
@@ -31,8 +62,74 @@ func SetupRouter(r *gin.Engine) {
 		authorized.POST("/posts", controllers.CreatePost)
 		authorized.PUT("/posts/:id", controllers.UpdatePost)
 		authorized.DELETE("/posts/:id", controllers.DeletePost)
+		authorized.GET("/posts/:id/stats", controllers.GetPostStats)
+		authorized.GET("/me/stats", controllers.GetMyStats)
+		authorized.GET("/unfurl", controllers.UnfurlLink)
+		authorized.POST("/stream-tickets", controllers.CreateStreamTicket)
+
+		authorized.GET("/feed", controllers.GetFeed)
+		authorized.POST("/users/:id/follow", controllers.FollowUser)
+		authorized.DELETE("/users/:id/follow", controllers.UnfollowUser)
+		authorized.POST("/tags/:name/follow", controllers.FollowTag)
+		authorized.DELETE("/tags/:name/follow", controllers.UnfollowTag)
+
+		authorized.POST("/posts/:id/bookmark", controllers.BookmarkPost)
+		authorized.DELETE("/posts/:id/bookmark", controllers.UnbookmarkPost)
+		authorized.POST("/posts/:id/comments", controllers.CreateComment)
+		authorized.GET("/posts/:id/collaborators", controllers.GetCollaborators)
+		authorized.POST("/posts/:id/collaborators", controllers.InviteCollaborator)
+		authorized.PUT("/posts/:id/collaborators/:userId", controllers.UpdateCollaboratorRole)
+		authorized.DELETE("/posts/:id/collaborators/:userId", controllers.RemoveCollaborator)
+		authorized.GET("/me/invitations", controllers.GetMyInvitations)
+		authorized.POST("/invitations/:id/accept", controllers.AcceptInvitation)
+		authorized.POST("/invitations/:id/decline", controllers.DeclineInvitation)
+
+		authorized.GET("/me/publications", controllers.GetMyPublications)
+		authorized.POST("/publications", controllers.CreatePublication)
+		authorized.PUT("/publications/:slug", controllers.UpdatePublication)
+		authorized.POST("/publications/:slug/members", controllers.AddPublicationMember)
+		authorized.PUT("/publications/:slug/members/:userId", controllers.UpdatePublicationMember)
+		authorized.DELETE("/publications/:slug/members/:userId", controllers.RemovePublicationMember)
+		authorized.GET("/publications/:slug/submissions", controllers.GetPublicationSubmissions)
+		authorized.POST("/publications/:slug/posts/:postId/approve", middleware.Audit("publication.post.approve"), controllers.ApprovePublicationPost)
+		authorized.POST("/publications/:slug/posts/:postId/reject", middleware.Audit("publication.post.reject"), controllers.RejectPublicationPost)
+		authorized.PUT("/publications/:slug/posts/:postId", controllers.UpdatePublicationPost)
+		authorized.POST("/posts/:id/publication", controllers.SubmitPostToPublication)
+		authorized.DELETE("/posts/:id/publication", middleware.Audit("publication.post.withdraw"), controllers.WithdrawPostFromPublication)
+
+		authorized.GET("/me/series", controllers.GetMySeries)
+		authorized.POST("/series", controllers.CreateSeries)
+		authorized.PUT("/series/:slug", controllers.UpdateSeries)
+		authorized.DELETE("/series/:slug", controllers.DeleteSeries)
+		authorized.POST("/series/:slug/posts", controllers.AddSeriesPost)
+		authorized.PUT("/series/:slug/posts", controllers.ReorderSeries)
+		authorized.DELETE("/series/:slug/posts/:postId", controllers.RemoveSeriesPost)
+
+		authorized.GET("/notifications", controllers.GetNotifications)
+		authorized.GET("/notifications/unread-count", controllers.GetUnreadNotificationCount)
+		authorized.POST("/notifications/read-all", controllers.MarkAllNotificationsRead)
+		authorized.POST("/notifications/:id/read", controllers.MarkNotificationRead)
+		authorized.GET("/notifications/preferences", controllers.GetNotificationPreferences)
+		authorized.PUT("/notifications/preferences", controllers.UpdateNotificationPreferences)
+
+		authorized.GET("/me/highlights", controllers.GetMyHighlights)
+		authorized.GET("/posts/:id/highlights", controllers.GetPostHighlights)
+		authorized.POST("/posts/:id/highlights", controllers.CreateHighlight)
+		authorized.DELETE("/highlights/:id", controllers.DeleteHighlight)
+
+		authorized.GET("/me/lists", controllers.GetMyReadingLists)
+		authorized.POST("/me/lists", controllers.CreateReadingList)
+		authorized.PUT("/lists/:id", controllers.UpdateReadingList)
+		authorized.DELETE("/lists/:id", controllers.DeleteReadingList)
+		authorized.POST("/lists/:id/items", controllers.AddReadingListItem)
+		authorized.PUT("/lists/:id/items", controllers.ReorderReadingList)
+		authorized.DELETE("/lists/:id/items/:postId", controllers.RemoveReadingListItem)
 	}
 
+	r.GET("/posts/:id/events", controllers.StreamPostEvents)
+	r.GET("/notifications/stream", middleware.StreamAuthMiddleware(), controllers.StreamNotifications)
+	r.GET("/posts/:id/collaborate", middleware.StreamAuthMiddleware(), controllers.CollaborationSocket)
+
 	r.POST("/admin/login", controllers.LoginAdmin)
 	admin := r.Group("/admin")
 	admin.Use(middleware.AuthMiddleware())
@@ -40,7 +137,13 @@ func SetupRouter(r *gin.Engine) {
 	{
 
 		admin.GET("/users", controllers.GetAllUsers)
-		admin.PUT("/users/:id/role", controllers.UpdateUserRole)
+		admin.PUT("/users/:id/role", middleware.Audit("user.role.update"), controllers.UpdateUserRole)
+		admin.GET("/audit", controllers.GetAuditLogs)
+		admin.GET("/stats", controllers.GetAdminStats)
+
+		admin.PUT("/posts/:id/pin", middleware.Audit("post.pin"), controllers.PinPost)
+		admin.DELETE("/posts/:id/pin", middleware.Audit("post.unpin"), controllers.UnpinPost)
+		admin.PUT("/featured", middleware.Audit("featured.update"), controllers.UpdateFeaturedPosts)
 
 	}
 
@@ -0,0 +1,248 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ActivityView = "view"
+	ActivityRead = "read"
+)
+
+// ActivityEvent is a view or read of a post by one visitor.
+type ActivityEvent struct {
+	Kind      string
+	PostID    uint
+	VisitorID string
+	UserID    *uint
+	Referrer  string
+	At        time.Time
+}
+
+var botUserAgent = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|preview|facebookexternalhit|embedly|curl|wget|python-requests|go-http-client|headless|lighthouse`)
+
+// IsBot reports whether a user agent looks automated. Requests without a user
+// agent are treated as bots too.
+func IsBot(userAgent string) bool {
+	return userAgent == "" || botUserAgent.MatchString(userAgent)
+}
+
+// VisitorID identifies a visitor without storing who they are: signed-in
+// users by their ID, everyone else by IP address and user agent.
+func VisitorID(userID *uint, ip, userAgent string) string {
+	var source string
+	if userID != nil {
+		source = fmt.Sprintf("user:%d", *userID)
+	} else {
+		source = "anon:" + ip + "|" + userAgent
+	}
+
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:16])
+}
+
+// ActivityRecorder buffers views and reads and writes them in batches from a
+// single goroutine, so recording never adds a database round trip to the
+// request. A visitor is counted once per post and kind within the dedupe
+// window; the window is tracked per instance.
+type ActivityRecorder struct {
+	events        chan ActivityEvent
+	dedupeWindow  time.Duration
+	flushInterval time.Duration
+	batchSize     int
+	lastSeen      map[string]time.Time
+}
+
+const (
+	activityBuffer    = 4096
+	activityBatchSize = 500
+)
+
+var Activity *ActivityRecorder
+
+func NewActivityRecorder(dedupeWindow, flushInterval time.Duration) *ActivityRecorder {
+	return &ActivityRecorder{
+		events:        make(chan ActivityEvent, activityBuffer),
+		dedupeWindow:  dedupeWindow,
+		flushInterval: flushInterval,
+		batchSize:     activityBatchSize,
+		lastSeen:      make(map[string]time.Time),
+	}
+}
+
+// Record queues an event. When the buffer is full the event is dropped
+// rather than slowing down the request.
+func (r *ActivityRecorder) Record(event ActivityEvent) {
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+
+	select {
+	case r.events <- event:
+	default:
+		log.Printf("Dropping %s event for post %d: recorder buffer full", event.Kind, event.PostID)
+	}
+}
+
+// Run writes queued events until ctx is cancelled, then flushes what is left.
+func (r *ActivityRecorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	var batch []ActivityEvent
+	flush := func() {
+		r.forgetBefore(time.Now().Add(-r.dedupeWindow))
+		if len(batch) == 0 {
+			return
+		}
+		if err := writeActivity(batch); err != nil {
+			// One bad row (say, for a post deleted in the meantime) fails the
+			// whole transaction; write the events one by one to keep the rest.
+			log.Printf("Failed to write %d activity events, retrying individually: %v", len(batch), err)
+			for _, event := range batch {
+				if err := writeActivity([]ActivityEvent{event}); err != nil {
+					log.Printf("Dropping %s event for post %d: %v", event.Kind, event.PostID, err)
+				}
+			}
+		}
+		batch = nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			for {
+				select {
+				case event := <-r.events:
+					if r.firstInWindow(event) {
+						batch = append(batch, event)
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		case event := <-r.events:
+			if r.firstInWindow(event) {
+				batch = append(batch, event)
+			}
+			if len(batch) >= r.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+func (r *ActivityRecorder) firstInWindow(event ActivityEvent) bool {
+	key := fmt.Sprintf("%s:%d:%s", event.Kind, event.PostID, event.VisitorID)
+	if last, ok := r.lastSeen[key]; ok && event.At.Sub(last) < r.dedupeWindow {
+		return false
+	}
+	r.lastSeen[key] = event.At
+	return true
+}
+
+func (r *ActivityRecorder) forgetBefore(cutoff time.Time) {
+	for key, seen := range r.lastSeen {
+		if seen.Before(cutoff) {
+			delete(r.lastSeen, key)
+		}
+	}
+}
+
+// writeActivity stores the rows and bumps the per-post counters in one
+// transaction.
+func writeActivity(batch []ActivityEvent) error {
+	var views []models.PostView
+	var reads []models.PostRead
+	viewCounts := make(map[uint]int)
+	readCounts := make(map[uint]int)
+
+	for _, event := range batch {
+		switch event.Kind {
+		case ActivityView:
+			views = append(views, models.PostView{
+				PostID:    event.PostID,
+				VisitorID: event.VisitorID,
+				UserID:    event.UserID,
+				Referrer:  truncateRunes(event.Referrer, 255),
+				CreatedAt: event.At,
+			})
+			viewCounts[event.PostID]++
+		case ActivityRead:
+			reads = append(reads, models.PostRead{
+				PostID:    event.PostID,
+				VisitorID: event.VisitorID,
+				UserID:    event.UserID,
+				CreatedAt: event.At,
+			})
+			readCounts[event.PostID]++
+		}
+	}
+
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if len(views) > 0 {
+			if err := tx.CreateInBatches(&views, 100).Error; err != nil {
+				return err
+			}
+		}
+		if len(reads) > 0 {
+			if err := tx.CreateInBatches(&reads, 100).Error; err != nil {
+				return err
+			}
+		}
+
+		for postID, count := range viewCounts {
+			if err := tx.Model(&models.Post{}).Where("id = ?", postID).
+				UpdateColumn("view_count", gorm.Expr("view_count + ?", count)).Error; err != nil {
+				return err
+			}
+		}
+		for postID, count := range readCounts {
+			if err := tx.Model(&models.Post{}).Where("id = ?", postID).
+				UpdateColumn("read_count", gorm.Expr("read_count + ?", count)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RecordActivity queues an event on the global recorder. It is a no-op when
+// the recorder has not been started.
+func RecordActivity(event ActivityEvent) {
+	if Activity == nil {
+		return
+	}
+	Activity.Record(event)
+}
+
+// StartActivityRecorder sets up the global recorder. VIEW_DEDUPE_WINDOW
+// (default 30m) is how long a repeat visit by the same visitor is ignored.
+func StartActivityRecorder(ctx context.Context) error {
+	dedupeWindow := 30 * time.Minute
+	if value := os.Getenv("VIEW_DEDUPE_WINDOW"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid VIEW_DEDUPE_WINDOW: %w", err)
+		}
+		dedupeWindow = parsed
+	}
+
+	Activity = NewActivityRecorder(dedupeWindow, 5*time.Second)
+	go Activity.Run(ctx)
+	return nil
+}
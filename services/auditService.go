@@ -0,0 +1,84 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"encoding/json"
+)
+
+const auditContextKey = "audit"
+
+// AuditTarget describes what an audited request changed.
+type AuditTarget struct {
+	Type   string
+	ID     uint
+	Before interface{}
+	After  interface{}
+}
+
+// SetAuditTarget lets a handler describe what it changed; c is the request's
+// *gin.Context. The Audit middleware writes the entry once the handler has
+// finished successfully.
+func SetAuditTarget(c interface{ Set(string, interface{}) }, targetType string, targetID uint, before, after interface{}) {
+	c.Set(auditContextKey, AuditTarget{Type: targetType, ID: targetID, Before: before, After: after})
+}
+
+// AuditTargetOf returns the target set by SetAuditTarget, if any.
+func AuditTargetOf(c interface {
+	Get(string) (interface{}, bool)
+}) (AuditTarget, bool) {
+	value, exists := c.Get(auditContextKey)
+	if !exists {
+		return AuditTarget{}, false
+	}
+	target, ok := value.(AuditTarget)
+	return target, ok
+}
+
+type AuditEntry struct {
+	ActorID    uint
+	Action     string
+	TargetType string
+	TargetID   uint
+	Before     interface{}
+	After      interface{}
+	IP         string
+}
+
+// RecordAudit appends an entry to the audit log. Before and After are stored as JSON snapshots.
+func RecordAudit(entry AuditEntry) error {
+	before, err := marshalAuditState(entry.Before)
+	if err != nil {
+		return err
+	}
+
+	after, err := marshalAuditState(entry.After)
+	if err != nil {
+		return err
+	}
+
+	log := models.AuditLog{
+		ActorID:    entry.ActorID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Before:     before,
+		After:      after,
+		IP:         entry.IP,
+	}
+
+	return config.DB.Create(&log).Error
+}
+
+func marshalAuditState(state interface{}) (string, error) {
+	if state == nil {
+		return "", nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
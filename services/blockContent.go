@@ -0,0 +1,301 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+const (
+	BlockParagraph = "paragraph"
+	BlockHeading   = "header"
+	BlockImage     = "image"
+	BlockCode      = "code"
+	BlockQuote     = "quote"
+	BlockEmbed     = "embed"
+
+	maxBlocks = 2000
+)
+
+// ErrInvalidBlocks wraps every validation error from ParseBlockDocument, so
+// handlers can tell a bad document from a server error.
+var ErrInvalidBlocks = errors.New("invalid block content")
+
+// BlockDocument is the editor.js output stored in Post.Content when the
+// content format is "blocks". Text fields of paragraphs, headings and quotes
+// hold inline HTML (bold, italic, links); it is sanitized with the rest of the
+// rendered HTML.
+type BlockDocument struct {
+	Time    int64   `json:"time,omitempty"`
+	Version string  `json:"version,omitempty"`
+	Blocks  []Block `json:"blocks"`
+}
+
+type Block struct {
+	ID   string          `json:"id,omitempty"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+
+	text    textBlock
+	heading headingBlock
+	image   imageBlock
+	code    codeBlock
+	quote   quoteBlock
+	embed   embedBlock
+}
+
+type textBlock struct {
+	Text string `json:"text"`
+}
+
+type headingBlock struct {
+	Text  string `json:"text"`
+	Level int    `json:"level"`
+}
+
+type imageBlock struct {
+	File struct {
+		URL string `json:"url"`
+	} `json:"file"`
+	URL     string `json:"url"` // simple-image tool
+	Caption string `json:"caption"`
+}
+
+func (b imageBlock) src() string {
+	if b.File.URL != "" {
+		return b.File.URL
+	}
+	return b.URL
+}
+
+type codeBlock struct {
+	Code     string `json:"code"`
+	Language string `json:"language"`
+}
+
+type quoteBlock struct {
+	Text    string `json:"text"`
+	Caption string `json:"caption"`
+}
+
+type embedBlock struct {
+	Service string `json:"service"`
+	Source  string `json:"source"`
+	Caption string `json:"caption"`
+}
+
+func invalidBlock(index int, format string, args ...interface{}) error {
+	return fmt.Errorf("%w: block %d: %s", ErrInvalidBlocks, index, fmt.Sprintf(format, args...))
+}
+
+func validWebURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// ParseBlockDocument decodes and validates a block document.
+func ParseBlockDocument(content string) (BlockDocument, error) {
+	var doc BlockDocument
+	decoder := json.NewDecoder(strings.NewReader(content))
+	if err := decoder.Decode(&doc); err != nil {
+		return doc, fmt.Errorf("%w: %v", ErrInvalidBlocks, err)
+	}
+	if len(doc.Blocks) == 0 {
+		return doc, fmt.Errorf("%w: the document has no blocks", ErrInvalidBlocks)
+	}
+	if len(doc.Blocks) > maxBlocks {
+		return doc, fmt.Errorf("%w: at most %d blocks are allowed", ErrInvalidBlocks, maxBlocks)
+	}
+
+	for i := range doc.Blocks {
+		block := &doc.Blocks[i]
+		if len(block.Data) == 0 {
+			return doc, invalidBlock(i, "data is required")
+		}
+
+		var err error
+		switch block.Type {
+		case BlockParagraph:
+			err = json.Unmarshal(block.Data, &block.text)
+		case BlockHeading:
+			if err = json.Unmarshal(block.Data, &block.heading); err == nil &&
+				(block.heading.Level < 1 || block.heading.Level > 6) {
+				return doc, invalidBlock(i, "heading level must be between 1 and 6")
+			}
+			if err == nil && strings.TrimSpace(block.heading.Text) == "" {
+				return doc, invalidBlock(i, "heading text is required")
+			}
+		case BlockImage:
+			if err = json.Unmarshal(block.Data, &block.image); err == nil && !validWebURL(block.image.src()) {
+				return doc, invalidBlock(i, "image needs an http(s) url")
+			}
+		case BlockCode:
+			err = json.Unmarshal(block.Data, &block.code)
+		case BlockQuote:
+			err = json.Unmarshal(block.Data, &block.quote)
+		case BlockEmbed:
+			if err = json.Unmarshal(block.Data, &block.embed); err == nil && !validWebURL(block.embed.Source) {
+				return doc, invalidBlock(i, "embed needs an http(s) source")
+			}
+		default:
+			return doc, invalidBlock(i, "unknown type %q", block.Type)
+		}
+		if err != nil {
+			return doc, invalidBlock(i, "%v", err)
+		}
+	}
+
+	return doc, nil
+}
+
+// HTML renders the document. The result still has to go through the
+// sanitizer, see RenderContent.
+func (doc BlockDocument) HTML() string {
+	var b strings.Builder
+	for _, block := range doc.Blocks {
+		switch block.Type {
+		case BlockParagraph:
+			fmt.Fprintf(&b, "<p>%s</p>\n", block.text.Text)
+		case BlockHeading:
+			fmt.Fprintf(&b, "<h%d>%s</h%d>\n", block.heading.Level, block.heading.Text, block.heading.Level)
+		case BlockImage:
+			fmt.Fprintf(&b, "<figure><img src=\"%s\" alt=\"%s\">", html.EscapeString(block.image.src()), html.EscapeString(plainText(block.image.Caption)))
+			if block.image.Caption != "" {
+				fmt.Fprintf(&b, "<figcaption>%s</figcaption>", block.image.Caption)
+			}
+			b.WriteString("</figure>\n")
+		case BlockCode:
+			class := ""
+			if block.code.Language != "" {
+				class = fmt.Sprintf(" class=\"language-%s\"", html.EscapeString(block.code.Language))
+			}
+			fmt.Fprintf(&b, "<pre><code%s>%s</code></pre>\n", class, html.EscapeString(block.code.Code))
+		case BlockQuote:
+			fmt.Fprintf(&b, "<blockquote><p>%s</p>", block.quote.Text)
+			if block.quote.Caption != "" {
+				fmt.Fprintf(&b, "<cite>%s</cite>", block.quote.Caption)
+			}
+			b.WriteString("</blockquote>\n")
+		case BlockEmbed:
+			fmt.Fprintf(&b, "<figure><a href=\"%s\">%s</a>", html.EscapeString(block.embed.Source), html.EscapeString(block.embed.Source))
+			if block.embed.Caption != "" {
+				fmt.Fprintf(&b, "<figcaption>%s</figcaption>", block.embed.Caption)
+			}
+			b.WriteString("</figure>\n")
+		}
+	}
+	return b.String()
+}
+
+// Markdown converts the document to Markdown. Inline formatting is kept as
+// (sanitized) HTML, which Markdown allows.
+func (doc BlockDocument) Markdown() string {
+	var parts []string
+	for _, block := range doc.Blocks {
+		switch block.Type {
+		case BlockParagraph:
+			parts = append(parts, contentPolicy.Sanitize(block.text.Text))
+		case BlockHeading:
+			parts = append(parts, strings.Repeat("#", block.heading.Level)+" "+contentPolicy.Sanitize(block.heading.Text))
+		case BlockImage:
+			parts = append(parts, fmt.Sprintf("![%s](%s)", plainText(block.image.Caption), block.image.src()))
+		case BlockCode:
+			fence := "```"
+			for strings.Contains(block.code.Code, fence) {
+				fence += "`"
+			}
+			parts = append(parts, fence+block.code.Language+"\n"+block.code.Code+"\n"+fence)
+		case BlockQuote:
+			quote := "> " + strings.ReplaceAll(contentPolicy.Sanitize(block.quote.Text), "\n", "\n> ")
+			if block.quote.Caption != "" {
+				quote += "\n>\n> — " + contentPolicy.Sanitize(block.quote.Caption)
+			}
+			parts = append(parts, quote)
+		case BlockEmbed:
+			parts = append(parts, fmt.Sprintf("<%s>", block.embed.Source))
+		}
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+// Text returns the readable text of the document, one block per paragraph.
+// Images and embeds contribute only their captions.
+func (doc BlockDocument) Text() string {
+	var parts []string
+	for _, block := range doc.Blocks {
+		var text string
+		switch block.Type {
+		case BlockParagraph:
+			text = plainText(block.text.Text)
+		case BlockHeading:
+			text = plainText(block.heading.Text)
+		case BlockImage:
+			text = plainText(block.image.Caption)
+		case BlockCode:
+			text = block.code.Code
+		case BlockQuote:
+			text = plainText(block.quote.Text)
+		case BlockEmbed:
+			text = plainText(block.embed.Caption)
+		}
+		if strings.TrimSpace(text) != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// FirstImage returns the URL of the first image block, or "".
+func (doc BlockDocument) FirstImage() string {
+	for _, block := range doc.Blocks {
+		if block.Type == BlockImage {
+			return block.image.src()
+		}
+	}
+	return ""
+}
+
+// Excerpt returns the text of the leading paragraphs, cut at a word boundary
+// to at most maxRunes characters.
+func (doc BlockDocument) Excerpt(maxRunes int) string {
+	var parts []string
+	for _, block := range doc.Blocks {
+		if block.Type != BlockParagraph {
+			continue
+		}
+		if text := strings.TrimSpace(plainText(block.text.Text)); text != "" {
+			parts = append(parts, text)
+		}
+		if len([]rune(strings.Join(parts, " "))) >= maxRunes {
+			break
+		}
+	}
+	return truncateWords(strings.Join(parts, " "), maxRunes)
+}
+
+var stripTags = bluemonday.StrictPolicy()
+
+// plainText drops the tags from inline HTML and decodes entities.
+func plainText(fragment string) string {
+	return html.UnescapeString(stripTags.Sanitize(fragment))
+}
+
+func truncateWords(text string, maxRunes int) string {
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text
+	}
+
+	// Break at the last space unless that throws away more than half, as
+	// happens with CJK text, which has no spaces.
+	cut := string(runes[:maxRunes])
+	if i := strings.LastIndexAny(cut, " \n\t"); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
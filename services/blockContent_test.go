@@ -0,0 +1,111 @@
+package services
+
+import (
+	"backend/models"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseBlockDocumentRejectsInvalidDocuments(t *testing.T) {
+	for name, content := range map[string]string{
+		"not json":          `<p>hello</p>`,
+		"no blocks":         `{"blocks": []}`,
+		"missing data":      `{"blocks": [{"type": "paragraph"}]}`,
+		"unknown type":      `{"blocks": [{"type": "table", "data": {}}]}`,
+		"heading level 0":   `{"blocks": [{"type": "header", "data": {"text": "Title", "level": 0}}]}`,
+		"heading level 7":   `{"blocks": [{"type": "header", "data": {"text": "Title", "level": 7}}]}`,
+		"empty heading":     `{"blocks": [{"type": "header", "data": {"text": "  ", "level": 2}}]}`,
+		"image without url": `{"blocks": [{"type": "image", "data": {"caption": "x"}}]}`,
+		"javascript image":  `{"blocks": [{"type": "image", "data": {"url": "javascript:alert(1)"}}]}`,
+		"relative embed":    `{"blocks": [{"type": "embed", "data": {"source": "/watch?v=1"}}]}`,
+		"wrong data type":   `{"blocks": [{"type": "paragraph", "data": {"text": 42}}]}`,
+		"too many blocks":   `{"blocks": [` + strings.Repeat(`{"type": "paragraph", "data": {"text": "x"}},`, maxBlocks) + `{"type": "paragraph", "data": {"text": "x"}}]}`,
+	} {
+		if _, err := ParseBlockDocument(content); !errors.Is(err, ErrInvalidBlocks) {
+			t.Errorf("%s: err = %v, want ErrInvalidBlocks", name, err)
+		}
+	}
+}
+
+func TestParseBlockDocumentAcceptsEveryBlockType(t *testing.T) {
+	doc, err := ParseBlockDocument(`{"time": 1, "version": "2.28", "blocks": [
+		{"type": "header", "data": {"text": "Title", "level": 2}},
+		{"type": "paragraph", "data": {"text": "Hello <b>world</b>"}},
+		{"type": "image", "data": {"file": {"url": "https://img.example/a.png"}, "caption": "A"}},
+		{"type": "image", "data": {"url": "https://img.example/b.png"}},
+		{"type": "code", "data": {"code": "x := 1", "language": "go"}},
+		{"type": "quote", "data": {"text": "Quoted", "caption": "Someone"}},
+		{"type": "embed", "data": {"service": "youtube", "source": "https://video.example/1"}}
+	]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Blocks) != 7 {
+		t.Errorf("parsed %d blocks, want 7", len(doc.Blocks))
+	}
+	if image := doc.FirstImage(); image != "https://img.example/a.png" {
+		t.Errorf("FirstImage = %q", image)
+	}
+}
+
+func TestBlockDocumentText(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		blocks string
+		want   string
+	}{
+		{"inline markup", `{"type": "paragraph", "data": {"text": "Hello <b>world</b> &amp; <a href=\"https://x.example\">friends</a>"}}`, "Hello world & friends"},
+		{"heading", `{"type": "header", "data": {"text": "<i>Title</i>", "level": 1}}`, "Title"},
+		{"image caption", `{"type": "image", "data": {"url": "https://img.example/a.png", "caption": "A <b>cat</b>"}}`, "A cat"},
+		{"image without caption", `{"type": "image", "data": {"url": "https://img.example/a.png"}}`, ""},
+		{"code kept verbatim", `{"type": "code", "data": {"code": "if a < b {\n}"}}`, "if a < b {\n}"},
+		{"quote without caption", `{"type": "quote", "data": {"text": "Quoted", "caption": "Someone"}}`, "Quoted"},
+		{"embed caption", `{"type": "embed", "data": {"source": "https://video.example/1", "caption": "Clip"}}`, "Clip"},
+		{"blank paragraph", `{"type": "paragraph", "data": {"text": "  "}}`, ""},
+		{
+			"paragraphs joined by blank lines",
+			`{"type": "paragraph", "data": {"text": "One"}}, {"type": "paragraph", "data": {"text": ""}}, {"type": "paragraph", "data": {"text": "Two"}}`,
+			"One\n\nTwo",
+		},
+	} {
+		doc, err := ParseBlockDocument(`{"blocks": [` + test.blocks + `]}`)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if got := doc.Text(); got != test.want {
+			t.Errorf("%s: Text() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestContentTextIsTheReadableText(t *testing.T) {
+	for _, test := range []struct {
+		format string
+		source string
+		want   string
+	}{
+		{models.ContentFormatMarkdown, "# Title\n\nHello **@ana** and [docs](https://x.example).", "Title\n\n\nHello @ana and docs."},
+		{models.ContentFormatHTML, "<h2>Title</h2><p>Hello <em>@ana</em></p><script>x()</script>", "Title\n\nHello @ana"},
+		{models.ContentFormatBlocks, `{"blocks": [{"type": "paragraph", "data": {"text": "Hello <b>@ana</b>"}}]}`, "Hello @ana"},
+	} {
+		contentHTML, err := RenderContent(test.format, test.source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		post := models.Post{ContentFormat: test.format, Content: test.source, ContentHTML: contentHTML}
+
+		got := ContentText(post)
+		if got != test.want {
+			t.Errorf("%s: ContentText = %q, want %q", test.format, got, test.want)
+		}
+		if exported, err := ConvertContent(post, ExportText); err != nil || exported != got {
+			t.Errorf("%s: ?format=text = %q, %v, want the same text as ContentText", test.format, exported, err)
+		}
+	}
+
+	if got := ContentText(models.Post{ContentFormat: models.ContentFormatBlocks, Content: "not json"}); got != "" {
+		t.Errorf("invalid block document: ContentText = %q, want empty", got)
+	}
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var collaboratorRoleRank = map[string]int{
+	models.CollaboratorViewer: 1,
+	models.CollaboratorEditor: 2,
+	models.CollaboratorOwner:  3,
+}
+
+func ValidCollaboratorRole(role string) bool {
+	return collaboratorRoleRank[role] > 0
+}
+
+// RoleAtLeast reports whether role grants everything minimum does. An empty
+// role (no access) never does.
+func RoleAtLeast(role string, minimum string) bool {
+	return role != "" && collaboratorRoleRank[role] >= collaboratorRoleRank[minimum]
+}
+
+// PostRole returns the user's role on the post: owner for its author, the
+// role of an accepted invitation, or "" when the user has no access.
+func PostRole(post models.Post, userID uint) (string, error) {
+	if post.UserID == userID {
+		return models.CollaboratorOwner, nil
+	}
+
+	var collaborator models.PostCollaborator
+	err := config.DB.Where("post_id = ? AND user_id = ? AND status = ?", post.ID, userID, models.InvitationAccepted).
+		First(&collaborator).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return collaborator.Role, nil
+}
+
+// CanCollaborate reports whether the user may join the live editing session
+// of a post: any of its owners, editors or viewers.
+func CanCollaborate(post models.Post, userID uint) (bool, error) {
+	role, err := PostRole(post, userID)
+	return role != "", err
+}
+
+// AuthorCollaborators limits a Collaborators preload to accepted co-authors.
+func AuthorCollaborators(db *gorm.DB) *gorm.DB {
+	return db.Where("status = ? AND role IN ?", models.InvitationAccepted, models.AuthorRoles).Order("id ASC")
+}
+
+// PreloadAuthors is a scope that loads everything needed to render a post's
+// authors: the original author and the accepted co-authors.
+func PreloadAuthors(db *gorm.DB) *gorm.DB {
+	return db.Preload("User").
+		Preload("Collaborators", AuthorCollaborators).
+		Preload("Collaborators.User")
+}
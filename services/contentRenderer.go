@@ -0,0 +1,162 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"bytes"
+	"errors"
+	"log"
+	"regexp"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+	"gorm.io/gorm"
+)
+
+// Markdown is rendered with GitHub-flavoured extensions and code blocks are
+// highlighted with inline styles, so no stylesheet is needed on the client.
+// Raw HTML inside Markdown is kept here and cleaned up by the sanitizer.
+var markdown = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM,
+		highlighting.NewHighlighting(
+			highlighting.WithStyle("github"),
+			highlighting.WithFormatOptions(chromahtml.WithClasses(false)),
+		),
+	),
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+var contentPolicy = newContentPolicy()
+
+func newContentPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	// Only what the highlighter emits
+	policy.AllowStyles("color", "background-color", "font-weight", "font-style", "text-decoration", "display").
+		OnElements("pre", "span")
+	return policy
+}
+
+// Formats a post's content can be exported in, see ConvertContent.
+const (
+	ExportHTML     = "html"
+	ExportMarkdown = "markdown"
+	ExportText     = "text"
+)
+
+var ErrUnsupportedExport = errors.New("content cannot be exported in this format")
+
+func ValidContentFormat(format string) bool {
+	return format == models.ContentFormatMarkdown || format == models.ContentFormatHTML ||
+		format == models.ContentFormatBlocks
+}
+
+// RenderContent turns a post's source into the HTML that is safe to serve:
+// Markdown and block documents are rendered first, and either way the result
+// is sanitized against an allow-list of tags and attributes, then headings
+// get their anchors. Invalid block documents fail with ErrInvalidBlocks.
+func RenderContent(format, source string) (string, error) {
+	switch format {
+	case models.ContentFormatMarkdown:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(source), &buf); err != nil {
+			return "", err
+		}
+		source = buf.String()
+	case models.ContentFormatBlocks:
+		doc, err := ParseBlockDocument(source)
+		if err != nil {
+			return "", err
+		}
+		source = doc.HTML()
+	}
+	return AnchorHeadings(contentPolicy.Sanitize(source)), nil
+}
+
+// ContentCoverImage returns the first image of a block document, or "" for
+// other formats.
+func ContentCoverImage(format, source string) string {
+	if format != models.ContentFormatBlocks {
+		return ""
+	}
+	doc, err := ParseBlockDocument(source)
+	if err != nil {
+		return ""
+	}
+	return doc.FirstImage()
+}
+
+// ContentText returns the text that highlight offsets and mentions refer to:
+// the post's readable text, as exported with ?format=text.
+func ContentText(post models.Post) string {
+	text, err := ConvertContent(post, ExportText)
+	if err != nil {
+		return ""
+	}
+	return text
+}
+
+// ConvertContent exports a post's content as HTML, Markdown or plain text.
+// Markdown is only available for posts written in Markdown or blocks.
+func ConvertContent(post models.Post, target string) (string, error) {
+	if target == ExportHTML {
+		return post.ContentHTML, nil
+	}
+
+	switch post.ContentFormat {
+	case models.ContentFormatBlocks:
+		doc, err := ParseBlockDocument(post.Content)
+		if err != nil {
+			return "", err
+		}
+		switch target {
+		case ExportMarkdown:
+			return doc.Markdown(), nil
+		case ExportText:
+			return doc.Text(), nil
+		}
+	case models.ContentFormatMarkdown:
+		if target == ExportMarkdown {
+			return post.Content, nil
+		}
+	}
+
+	if target == ExportText {
+		return HTMLText(post.ContentHTML), nil
+	}
+	return "", ErrUnsupportedExport
+}
+
+// BackfillRenderedContent renders posts saved before content was rendered on
+// save, and fills in their reading stats and table of contents.
+func BackfillRenderedContent() error {
+	var posts []models.Post
+	return config.DB.Select("id, description, content, content_format").
+		Where("content_html IS NULL OR content_html = '' OR (word_count = 0 AND content <> '') OR toc IS NULL OR toc = ''").
+		FindInBatches(&posts, 100, func(tx *gorm.DB, batch int) error {
+			for _, post := range posts {
+				rendered, err := RenderContent(post.ContentFormat, post.Content)
+				if err != nil {
+					log.Printf("Failed to render post %d: %v", post.ID, err)
+					continue
+				}
+				stats := ComputeReadingStats(post.ContentFormat, post.Content, rendered, post.Description)
+				if err := config.DB.Model(&models.Post{}).Where("id = ?", post.ID).
+					UpdateColumns(map[string]interface{}{
+						"content_html":    rendered,
+						"word_count":      stats.WordCount,
+						"reading_minutes": stats.ReadingMinutes,
+						"excerpt":         stats.Excerpt,
+						"toc":             TableOfContents(rendered),
+					}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
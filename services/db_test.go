@@ -0,0 +1,35 @@
+package services
+
+import (
+	config "backend/configs"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// useTestDB points config.DB at an empty in-memory SQLite database with
+// foreign keys enforced and the given models migrated.
+func useTestDB(t *testing.T, models ...interface{}) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared&_pragma=foreign_keys(1)"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := config.DB
+	config.DB = db
+	t.Cleanup(func() {
+		config.DB = previous
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+}
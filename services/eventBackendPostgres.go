@@ -0,0 +1,99 @@
+package services
+
+import (
+	config "backend/configs"
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const postgresEventChannel = "blog_events"
+
+// postgresBackend fans events out across replicas with LISTEN/NOTIFY, so no
+// extra infrastructure is needed when the app already runs on Postgres.
+// Publishing goes through the shared pool; listening needs its own
+// connection. NOTIFY payloads are limited to 8000 bytes.
+type postgresBackend struct {
+	dsn string
+}
+
+func NewPostgresEventBackend(dsn string) EventBackend {
+	return &postgresBackend{dsn: dsn}
+}
+
+func (b *postgresBackend) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return config.DB.WithContext(ctx).
+		Exec("SELECT pg_notify(?, ?)", postgresEventChannel, string(payload)).Error
+}
+
+func (b *postgresBackend) Start(ctx context.Context, deliver func(Event)) error {
+	conn, err := b.listen(ctx)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for {
+			b.receive(ctx, conn, deliver)
+			conn.Close(context.Background())
+
+			// Reconnect until the context is cancelled.
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(5 * time.Second):
+				}
+
+				if conn, err = b.listen(ctx); err == nil {
+					break
+				}
+				log.Println("Failed to reconnect event listener:", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (b *postgresBackend) listen(ctx context.Context) (*pgx.Conn, error) {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.Exec(ctx, "LISTEN "+postgresEventChannel); err != nil {
+		conn.Close(ctx)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (b *postgresBackend) receive(ctx context.Context, conn *pgx.Conn, deliver func(Event)) {
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Println("Event listener connection lost:", err)
+			}
+			return
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			log.Println("Ignoring malformed event payload:", err)
+			continue
+		}
+
+		deliver(event)
+	}
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	config "backend/configs"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+)
+
+// Event is a real-time update delivered to subscribers of a topic such as
+// "post:12" or "user:3".
+type Event struct {
+	Topic string          `json:"topic"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// EventBackend carries events between replicas. Publish sends an event to
+// every replica, and Start hands each received event (including the ones this
+// replica published) to deliver until ctx is cancelled.
+type EventBackend interface {
+	Publish(ctx context.Context, event Event) error
+	Start(ctx context.Context, deliver func(Event)) error
+}
+
+const subscriberBuffer = 16
+
+func PostTopic(postID uint) string {
+	return fmt.Sprintf("post:%d", postID)
+}
+
+func UserTopic(userID uint) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// EventHub fans events out to the local subscribers of each topic.
+type EventHub struct {
+	backend     EventBackend
+	mu          sync.RWMutex
+	subscribers map[string]map[chan Event]struct{}
+}
+
+var Events *EventHub
+
+func NewEventHub(backend EventBackend) *EventHub {
+	return &EventHub{
+		backend:     backend,
+		subscribers: make(map[string]map[chan Event]struct{}),
+	}
+}
+
+// Start connects the hub to its backend. It returns once the backend is
+// listening; delivery continues in the background until ctx is cancelled.
+func (h *EventHub) Start(ctx context.Context) error {
+	return h.backend.Start(ctx, h.dispatch)
+}
+
+func (h *EventHub) Publish(topic string, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return h.backend.Publish(context.Background(), Event{Topic: topic, Type: eventType, Data: payload})
+}
+
+// Subscribe returns a channel of events for topic and a function that must be
+// called to stop the subscription.
+func (h *EventHub) Subscribe(topic string) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subscribers[topic] == nil {
+		h.subscribers[topic] = make(map[chan Event]struct{})
+	}
+	h.subscribers[topic][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers[topic], ch)
+			if len(h.subscribers[topic]) == 0 {
+				delete(h.subscribers, topic)
+			}
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe
+}
+
+// dispatch never blocks: a subscriber whose buffer is full misses the event
+// instead of stalling everyone else.
+func (h *EventHub) dispatch(event Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.subscribers[event.Topic] {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("Dropping %q event for slow subscriber on %s", event.Type, event.Topic)
+		}
+	}
+}
+
+// PublishEvent publishes through the global hub and logs failures. It is a
+// no-op when real-time events are not enabled.
+func PublishEvent(topic string, eventType string, data interface{}) {
+	if Events == nil {
+		return
+	}
+
+	if err := Events.Publish(topic, eventType, data); err != nil {
+		log.Printf("Failed to publish %q event on %s: %v", eventType, topic, err)
+	}
+}
+
+// memoryBackend delivers events within this process only.
+type memoryBackend struct {
+	mu      sync.RWMutex
+	deliver func(Event)
+}
+
+func NewMemoryEventBackend() EventBackend {
+	return &memoryBackend{}
+}
+
+func (b *memoryBackend) Start(ctx context.Context, deliver func(Event)) error {
+	b.mu.Lock()
+	b.deliver = deliver
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *memoryBackend) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	deliver := b.deliver
+	b.mu.RUnlock()
+
+	if deliver != nil {
+		deliver(event)
+	}
+	return nil
+}
+
+// StartEventHub sets up the global hub. EVENTS_BACKEND selects how events
+// reach other replicas: "memory" (default, single instance) or "postgres".
+func StartEventHub(ctx context.Context) error {
+	var backend EventBackend
+	switch os.Getenv("EVENTS_BACKEND") {
+	case "", "memory":
+		backend = NewMemoryEventBackend()
+	case "postgres":
+		backend = NewPostgresEventBackend(config.PostgresDSN())
+	default:
+		return fmt.Errorf("unsupported EVENTS_BACKEND: %s", os.Getenv("EVENTS_BACKEND"))
+	}
+
+	hub := NewEventHub(backend)
+	if err := hub.Start(ctx); err != nil {
+		return err
+	}
+
+	Events = hub
+	return nil
+}
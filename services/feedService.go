@@ -0,0 +1,100 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	feedWindow        = 30 * 24 * time.Hour
+	feedRecencyWeight = 1.0
+	feedClapWeight    = 0.5
+	// Age at which the recency part of the score has decayed to ~37%.
+	feedRecencyDecay = 48 * time.Hour
+)
+
+// BuildFeed returns one page of recent posts written by authors the user
+// follows or tagged with tags they follow, ranked by FeedScore, and the
+// number of posts in the whole feed. Every candidate is ranked on its ID,
+// date and claps alone; only the requested page is loaded in full.
+func BuildFeed(userID uint, now time.Time, page, perPage int) ([]models.Post, int64, error) {
+	followedAuthors := config.DB.Model(&models.Follow{}).
+		Select("followee_id").
+		Where("follower_id = ?", userID)
+
+	followedTags := config.DB.Table("post_tags").
+		Select("post_tags.post_id").
+		Joins("JOIN tag_follows ON tag_follows.tag_id = post_tags.tag_id").
+		Where("tag_follows.user_id = ?", userID)
+
+	var candidates []models.Post
+	err := config.DB.Model(&models.Post{}).
+		Select("id, created_at, claps").
+		Where("created_at >= ?", now.Add(-feedWindow)).
+		Where(config.DB.Where("user_id IN (?)", followedAuthors).Or("id IN (?)", followedTags)).
+		Where("user_id <> ?", userID).
+		Order("created_at DESC, id DESC").
+		Find(&candidates).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	RankFeed(candidates, now)
+
+	total := int64(len(candidates))
+	start := min((page-1)*perPage, len(candidates))
+	end := min(start+perPage, len(candidates))
+	if start == end {
+		return []models.Post{}, total, nil
+	}
+
+	ids := make([]uint, 0, end-start)
+	for _, candidate := range candidates[start:end] {
+		ids = append(ids, candidate.ID)
+	}
+
+	var posts []models.Post
+	if err := config.DB.Scopes(PreloadAuthors).
+		Preload("Tags").
+		Where("id IN ?", ids).
+		Find(&posts).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Put the page back in ranked order.
+	byID := make(map[uint]models.Post, len(posts))
+	for _, post := range posts {
+		byID[post.ID] = post
+	}
+	ranked := make([]models.Post, 0, len(posts))
+	for _, id := range ids {
+		if post, ok := byID[id]; ok {
+			ranked = append(ranked, post)
+		}
+	}
+	return ranked, total, nil
+}
+
+// FeedScore mixes an exponentially decaying recency term with a logarithmic
+// clap term, so a heavily clapped post can outrank a slightly newer one
+// without staying on top forever.
+func FeedScore(post models.Post, now time.Time) float64 {
+	age := now.Sub(post.CreatedAt)
+	if age < 0 {
+		age = 0
+	}
+
+	recency := math.Exp(-float64(age) / float64(feedRecencyDecay))
+	claps := math.Log10(1 + float64(post.Claps))
+
+	return feedRecencyWeight*recency + feedClapWeight*claps
+}
+
+func RankFeed(posts []models.Post, now time.Time) {
+	sort.SliceStable(posts, func(i, j int) bool {
+		return FeedScore(posts[i], now) > FeedScore(posts[j], now)
+	})
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"errors"
+)
+
+const (
+	MaxHighlightLength = 1000
+	// A re-anchored quote may differ from the original by at most this share
+	// of its length (edit distance) before the highlight is orphaned.
+	highlightMaxErrorRatio = 0.2
+	// How far from its old position, in characters, an edited quote is
+	// looked for. Exact matches are found anywhere in the post.
+	highlightSearchWindow = 2000
+)
+
+var ErrInvalidHighlightRange = errors.New("invalid highlight range")
+
+// QuoteAt returns the passage of a post's text (see ContentText) between the
+// given character offsets.
+func QuoteAt(content string, start, end int) (string, error) {
+	runes := []rune(content)
+	if start < 0 || end > len(runes) || start >= end || end-start > MaxHighlightLength {
+		return "", ErrInvalidHighlightRange
+	}
+	return string(runes[start:end]), nil
+}
+
+// ReanchorHighlights moves every highlight on the post to where its quote now
+// appears in the new text (see ContentText). Highlights whose quote can no
+// longer be found are marked as orphaned rather than deleted.
+func ReanchorHighlights(postID uint, content string) error {
+	var highlights []models.Highlight
+	if err := config.DB.Where("post_id = ?", postID).Find(&highlights).Error; err != nil {
+		return err
+	}
+
+	type passage struct {
+		start int
+		quote string
+	}
+	type anchor struct {
+		start, end int
+		found      bool
+	}
+	// Readers tend to highlight the same passages; locate each one once.
+	anchors := make(map[passage]anchor)
+
+	runes := []rune(content)
+	for _, highlight := range highlights {
+		key := passage{start: highlight.StartOffset, quote: highlight.Quote}
+		located, done := anchors[key]
+		if !done {
+			located.start, located.end, located.found = Reanchor(runes, []rune(highlight.Quote), highlight.StartOffset)
+			anchors[key] = located
+		}
+		start, end, found := located.start, located.end, located.found
+
+		updates := map[string]interface{}{"orphaned": !found}
+		if found {
+			updates["start_offset"] = start
+			updates["end_offset"] = end
+			updates["quote"] = string(runes[start:end])
+		}
+
+		if err := config.DB.Model(&highlight).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reanchor locates quote in content. An exact occurrence closest to hint wins;
+// otherwise the best approximate match within the allowed edit distance is
+// used. It returns character offsets into content.
+func Reanchor(content []rune, quote []rune, hint int) (int, int, bool) {
+	if len(quote) == 0 || len(content) == 0 {
+		return 0, 0, false
+	}
+
+	if start, ok := nearestExactMatch(content, quote, hint); ok {
+		return start, start + len(quote), true
+	}
+
+	// The approximate search is quadratic, so it only looks around the old
+	// position.
+	from := max(0, min(hint, len(content))-highlightSearchWindow)
+	to := min(len(content), max(hint, 0)+len(quote)+highlightSearchWindow)
+
+	maxErrors := int(float64(len(quote)) * highlightMaxErrorRatio)
+	start, end, found := approximateMatch(content[from:to], quote, hint-from, maxErrors)
+	return start + from, end + from, found
+}
+
+func nearestExactMatch(content []rune, quote []rune, hint int) (int, bool) {
+	best, found := 0, false
+	for i := 0; i+len(quote) <= len(content); i++ {
+		if !runesEqual(content[i:i+len(quote)], quote) {
+			continue
+		}
+		if !found || abs(i-hint) < abs(best-hint) {
+			best, found = i, true
+		}
+	}
+	return best, found
+}
+
+// approximateMatch finds the substring of content with the smallest edit
+// distance to quote (Sellers' algorithm), preferring matches near hint on
+// ties. It runs in O(len(content) * len(quote)) time and O(len(quote)) memory.
+func approximateMatch(content []rune, quote []rune, hint int, maxErrors int) (int, int, bool) {
+	m := len(quote)
+
+	prevCost := make([]int, m+1)
+	prevStart := make([]int, m+1)
+	curCost := make([]int, m+1)
+	curStart := make([]int, m+1)
+	for i := range prevCost {
+		prevCost[i] = i
+	}
+
+	bestCost, bestStart, bestEnd := maxErrors+1, 0, 0
+	for j := 1; j <= len(content); j++ {
+		curCost[0], curStart[0] = 0, j
+		for i := 1; i <= m; i++ {
+			substitution := prevCost[i-1]
+			if quote[i-1] != content[j-1] {
+				substitution++
+			}
+			cost, start := substitution, prevStart[i-1]
+
+			if skipText := prevCost[i] + 1; skipText < cost {
+				cost, start = skipText, prevStart[i]
+			}
+			if skipQuote := curCost[i-1] + 1; skipQuote < cost {
+				cost, start = skipQuote, curStart[i-1]
+			}
+
+			curCost[i], curStart[i] = cost, start
+		}
+
+		cost, start := curCost[m], curStart[m]
+		if cost < bestCost || (cost == bestCost && abs(start-hint) < abs(bestStart-hint)) {
+			bestCost, bestStart, bestEnd = cost, start, j
+		}
+
+		prevCost, curCost = curCost, prevCost
+		prevStart, curStart = curStart, prevStart
+	}
+
+	if bestCost > maxErrors || bestStart >= bestEnd {
+		return 0, 0, false
+	}
+	return bestStart, bestEnd, true
+}
+
+type TopHighlight struct {
+	StartOffset int    `json:"start_offset"`
+	EndOffset   int    `json:"end_offset"`
+	Quote       string `json:"quote"`
+	Count       int64  `json:"count"`
+}
+
+// MostHighlightedPassage returns the passage highlighted by the most readers,
+// or nil when the post has no active highlights.
+func MostHighlightedPassage(postID uint) (*TopHighlight, error) {
+	var top []TopHighlight
+	err := config.DB.Model(&models.Highlight{}).
+		Select("start_offset, end_offset, COUNT(*) AS count").
+		Where("post_id = ? AND orphaned = ?", postID, false).
+		Group("start_offset, end_offset").
+		Order("count DESC, start_offset ASC").
+		Limit(1).
+		Scan(&top).Error
+	if err != nil || len(top) == 0 {
+		return nil, err
+	}
+
+	var sample models.Highlight
+	if err := config.DB.Where("post_id = ? AND orphaned = ? AND start_offset = ? AND end_offset = ?",
+		postID, false, top[0].StartOffset, top[0].EndOffset).
+		First(&sample).Error; err != nil {
+		return nil, err
+	}
+	top[0].Quote = sample.Quote
+
+	return &top[0], nil
+}
+
+func runesEqual(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
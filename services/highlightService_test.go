@@ -0,0 +1,131 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQuoteAt(t *testing.T) {
+	content := "Xin chào thế giới"
+	for _, tc := range []struct {
+		name       string
+		start, end int
+		want       string
+		wantErr    bool
+	}{
+		{"ascii prefix", 0, 3, "Xin", false},
+		{"counts characters, not bytes", 4, 8, "chào", false},
+		{"to the end", 13, 17, "giới", false},
+		{"empty", 3, 3, "", true},
+		{"reversed", 5, 2, "", true},
+		{"negative start", -1, 2, "", true},
+		{"past the end", 10, 18, "", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := QuoteAt(content, tc.start, tc.end)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("QuoteAt = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := QuoteAt(strings.Repeat("a", MaxHighlightLength+1), 0, MaxHighlightLength+1); err == nil {
+		t.Error("accepted a highlight longer than MaxHighlightLength")
+	}
+}
+
+func TestReanchor(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		content   string
+		quote     string
+		hint      int
+		want      string
+		wantStart int
+		found     bool
+	}{
+		{
+			name:    "unchanged",
+			content: "The quick brown fox jumps over the lazy dog.",
+			quote:   "brown fox", hint: 10,
+			want: "brown fox", wantStart: 10, found: true,
+		},
+		{
+			name:    "text inserted before",
+			content: "Intro paragraph. The quick brown fox jumps over the lazy dog.",
+			quote:   "brown fox", hint: 10,
+			want: "brown fox", wantStart: 27, found: true,
+		},
+		{
+			name:    "nearest of several exact matches",
+			content: "fox one. fox two. fox three.",
+			quote:   "fox", hint: 17,
+			want: "fox", wantStart: 18, found: true,
+		},
+		{
+			name:    "small edit inside the quote",
+			content: "The quick brown fax jumps over the lazy dog.",
+			quote:   "quick brown fox jumps", hint: 4,
+			want: "quick brown fax jumps", wantStart: 4, found: true,
+		},
+		{
+			name:    "multibyte text",
+			content: "Hôm nay trời đẹp quá, đi chơi thôi.",
+			quote:   "trời đẹp", hint: 8,
+			want: "trời đẹp", wantStart: 8, found: true,
+		},
+		{
+			name:    "rewritten beyond the error ratio",
+			content: "Completely different words now.",
+			quote:   "quick brown fox", hint: 0,
+			found: false,
+		},
+		{
+			name:    "empty content",
+			content: "",
+			quote:   "fox", hint: 0,
+			found: false,
+		},
+		{
+			name:    "hint past the end",
+			content: "A short post about a brown fix.",
+			quote:   "brown fox", hint: 500,
+			want: "brown fix", wantStart: 21, found: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			content := []rune(tc.content)
+			start, end, found := Reanchor(content, []rune(tc.quote), tc.hint)
+			if found != tc.found {
+				t.Fatalf("found = %v, want %v", found, tc.found)
+			}
+			if !found {
+				return
+			}
+			if got := string(content[start:end]); got != tc.want || start != tc.wantStart {
+				t.Errorf("Reanchor = %q at %d, want %q at %d", got, start, tc.want, tc.wantStart)
+			}
+		})
+	}
+}
+
+func TestReanchorOnlySearchesNearTheHintForEdits(t *testing.T) {
+	filler := strings.Repeat("lorem ipsum ", highlightSearchWindow/6)
+	content := []rune("the quick brown fax " + filler + filler)
+
+	// An exact match is found anywhere.
+	if start, _, found := Reanchor(content, []rune("the quick brown fax"), len(content)-1); !found || start != 0 {
+		t.Errorf("exact match: found %v at %d, want 0", found, start)
+	}
+
+	// An edited quote is only looked for around its old position.
+	if _, _, found := Reanchor(content, []rune("the quick brown fox"), len(content)-1); found {
+		t.Error("approximate match found outside the search window")
+	}
+	if start, _, found := Reanchor(content, []rune("the quick brown fox"), 0); !found || start != 0 {
+		t.Errorf("approximate match near the hint: found %v at %d, want 0", found, start)
+	}
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"errors"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.]+)`)
+
+// NotificationEnabled reports whether the user wants notifications of the
+// given type. Types without a stored preference are enabled.
+func NotificationEnabled(userID uint, notificationType string) (bool, error) {
+	var preference models.NotificationPreference
+	err := config.DB.Where("user_id = ? AND type = ?", userID, notificationType).First(&preference).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return preference.Enabled, nil
+}
+
+// Notify stores a notification for its recipient, unless the recipient is the
+// actor or has switched that type off.
+func Notify(notification models.Notification) error {
+	if notification.UserID == notification.ActorID {
+		return nil
+	}
+
+	enabled, err := NotificationEnabled(notification.UserID, notification.Type)
+	if err != nil || !enabled {
+		return err
+	}
+
+	if err := config.DB.Create(&notification).Error; err != nil {
+		return err
+	}
+
+	PublishEvent(UserTopic(notification.UserID), "notification", notification)
+	return nil
+}
+
+// NotifyClap folds claps on the same post into one unread notification that
+// counts them and names the latest clapper.
+func NotifyClap(recipientID, actorID, postID uint, claps int) error {
+	if recipientID == actorID || claps <= 0 {
+		return nil
+	}
+
+	enabled, err := NotificationEnabled(recipientID, models.NotificationClap)
+	if err != nil || !enabled {
+		return err
+	}
+
+	result := config.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND type = ? AND post_id = ? AND is_read = ?", recipientID, models.NotificationClap, postID, false).
+		Updates(map[string]interface{}{
+			"count":    gorm.Expr("count + ?", claps),
+			"actor_id": actorID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		var notification models.Notification
+		if err := config.DB.Where("user_id = ? AND type = ? AND post_id = ? AND is_read = ?", recipientID, models.NotificationClap, postID, false).
+			First(&notification).Error; err != nil {
+			return err
+		}
+
+		PublishEvent(UserTopic(recipientID), "notification", notification)
+		return nil
+	}
+
+	notification := models.Notification{
+		UserID:  recipientID,
+		ActorID: actorID,
+		Type:    models.NotificationClap,
+		PostID:  &postID,
+		Count:   claps,
+	}
+	if err := config.DB.Create(&notification).Error; err != nil {
+		return err
+	}
+
+	PublishEvent(UserTopic(recipientID), "notification", notification)
+	return nil
+}
+
+// ParseMentions returns the distinct usernames mentioned as @username.
+func ParseMentions(text string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		username := strings.TrimRight(match[1], ".")
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+		usernames = append(usernames, username)
+	}
+
+	return usernames
+}
+
+// NotifyMentions notifies users mentioned in text. Usernames listed in
+// alreadyMentioned are skipped so that editing a post does not notify the
+// same people twice.
+func NotifyMentions(actorID uint, text string, alreadyMentioned []string, postID uint, commentID *uint) error {
+	skip := make(map[string]bool, len(alreadyMentioned))
+	for _, username := range alreadyMentioned {
+		skip[username] = true
+	}
+
+	var usernames []string
+	for _, username := range ParseMentions(text) {
+		if !skip[username] {
+			usernames = append(usernames, username)
+		}
+	}
+	if len(usernames) == 0 {
+		return nil
+	}
+
+	var users []models.User
+	if err := config.DB.Where("username IN ?", usernames).Find(&users).Error; err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		err := Notify(models.Notification{
+			UserID:    user.ID,
+			ActorID:   actorID,
+			Type:      models.NotificationMention,
+			PostID:    &postID,
+			CommentID: commentID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+)
+
+type PresenceUser struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Photo string `json:"photo"`
+}
+
+type PresenceMessage struct {
+	Type string          `json:"type"`
+	User *PresenceUser   `json:"user,omitempty"`
+	Data json.RawMessage `json:"data,omitempty"`
+	At   time.Time       `json:"at"`
+}
+
+// PresenceClient is one open editor connection. Send is drained by the
+// connection's writer; it is closed when the client leaves.
+type PresenceClient struct {
+	User PresenceUser
+	Send chan PresenceMessage
+}
+
+const presenceSendBuffer = 32
+
+func NewPresenceClient(user PresenceUser) *PresenceClient {
+	return &PresenceClient{User: user, Send: make(chan PresenceMessage, presenceSendBuffer)}
+}
+
+// PresenceHub tracks who is editing each post on this instance and relays
+// their cursor, selection and typing events to the others.
+type PresenceHub struct {
+	mu    sync.Mutex
+	rooms map[uint]map[*PresenceClient]struct{}
+}
+
+var Presence = NewPresenceHub()
+
+func NewPresenceHub() *PresenceHub {
+	return &PresenceHub{rooms: make(map[uint]map[*PresenceClient]struct{})}
+}
+
+// Join adds the client to the post's room, sends it the current member list
+// and announces it to everyone else.
+func (h *PresenceHub) Join(postID uint, client *PresenceClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	room := h.rooms[postID]
+	if room == nil {
+		room = make(map[*PresenceClient]struct{})
+		h.rooms[postID] = room
+	}
+	room[client] = struct{}{}
+
+	members, _ := json.Marshal(map[string]interface{}{"users": roomUsers(room)})
+	h.deliver(client, PresenceMessage{Type: "presence", Data: members, At: time.Now()})
+	h.broadcastLocked(postID, client, PresenceMessage{Type: "join", User: &client.User, At: time.Now()})
+}
+
+func (h *PresenceHub) Leave(postID uint, client *PresenceClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	room := h.rooms[postID]
+	if _, ok := room[client]; !ok {
+		return
+	}
+
+	delete(room, client)
+	close(client.Send)
+	if len(room) == 0 {
+		delete(h.rooms, postID)
+		return
+	}
+
+	h.broadcastLocked(postID, client, PresenceMessage{Type: "leave", User: &client.User, At: time.Now()})
+}
+
+// Broadcast relays a message from one client to everyone else in the room.
+func (h *PresenceHub) Broadcast(postID uint, from *PresenceClient, message PresenceMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	message.User = &from.User
+	message.At = time.Now()
+	h.broadcastLocked(postID, from, message)
+}
+
+func (h *PresenceHub) broadcastLocked(postID uint, from *PresenceClient, message PresenceMessage) {
+	for client := range h.rooms[postID] {
+		if client != from {
+			h.deliver(client, message)
+		}
+	}
+}
+
+// deliver drops the message if the client cannot keep up; cursor updates are
+// superseded by the next one anyway.
+func (h *PresenceHub) deliver(client *PresenceClient, message PresenceMessage) {
+	select {
+	case client.Send <- message:
+	default:
+		log.Printf("Dropping %q presence message for user %d", message.Type, client.User.ID)
+	}
+}
+
+func roomUsers(room map[*PresenceClient]struct{}) []PresenceUser {
+	seen := make(map[uint]bool)
+	users := make([]PresenceUser, 0, len(room))
+	for client := range room {
+		if seen[client.User.ID] {
+			continue
+		}
+		seen[client.User.ID] = true
+		users = append(users, client.User)
+	}
+	return users
+}
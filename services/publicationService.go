@@ -0,0 +1,73 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var publicationRoleRank = map[string]int{
+	models.PublicationWriter: 1,
+	models.PublicationEditor: 2,
+	models.PublicationOwner:  3,
+}
+
+func ValidPublicationRole(role string) bool {
+	return publicationRoleRank[role] > 0
+}
+
+// PublicationRoleAtLeast reports whether role grants everything minimum does.
+// An empty role (not a member) never does.
+func PublicationRoleAtLeast(role string, minimum string) bool {
+	return role != "" && publicationRoleRank[role] >= publicationRoleRank[minimum]
+}
+
+// PublicationRole returns the user's role in the publication, or "" when
+// they are not a member.
+func PublicationRole(publicationID, userID uint) (string, error) {
+	var member models.PublicationMember
+	err := config.DB.Where("publication_id = ? AND user_id = ?", publicationID, userID).First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return member.Role, nil
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slugify turns a name into a lowercase, dash separated URL segment.
+func Slugify(name string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// NotifyPublicationEditors sends an editorial notification to every editor
+// and owner of the publication.
+func NotifyPublicationEditors(publicationID, actorID, postID uint, message string) {
+	var editorIDs []uint
+	if err := config.DB.Model(&models.PublicationMember{}).
+		Where("publication_id = ? AND role IN ?", publicationID, []string{models.PublicationOwner, models.PublicationEditor}).
+		Pluck("user_id", &editorIDs).Error; err != nil {
+		log.Println("Error loading publication editors:", err)
+		return
+	}
+
+	for _, editorID := range editorIDs {
+		if err := Notify(models.Notification{
+			UserID:  editorID,
+			ActorID: actorID,
+			Type:    models.NotificationEditorial,
+			PostID:  &postID,
+			Message: message,
+		}); err != nil {
+			log.Println("Error creating editorial notification:", err)
+		}
+	}
+}
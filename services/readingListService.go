@@ -0,0 +1,94 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// DefaultReadingList returns the user's default list, creating it on first use.
+// When two requests race to create it, the loser reads the winner's list.
+func DefaultReadingList(userID uint) (*models.ReadingList, error) {
+	var list models.ReadingList
+	err := config.DB.Where("user_id = ? AND is_default = ?", userID, true).First(&list).Error
+	if err == nil {
+		return &list, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	list = models.ReadingList{
+		UserID:     userID,
+		Name:       models.DefaultReadingListName,
+		IsDefault:  true,
+		DefaultFor: &userID,
+	}
+	createErr := config.DB.Create(&list).Error
+	if createErr == nil {
+		return &list, nil
+	}
+
+	var existing models.ReadingList
+	if err := config.DB.Where("user_id = ? AND is_default = ?", userID, true).First(&existing).Error; err != nil {
+		return nil, createErr
+	}
+	return &existing, nil
+}
+
+// AddToReadingList appends the post at the end of the list. Adding a post that
+// is already in the list is a no-op.
+func AddToReadingList(listID uint, postID uint) (*models.ReadingListItem, error) {
+	var item models.ReadingListItem
+	err := config.DB.Where("reading_list_id = ? AND post_id = ?", listID, postID).First(&item).Error
+	if err == nil {
+		return &item, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var maxPosition *int
+	if err := config.DB.Model(&models.ReadingListItem{}).
+		Where("reading_list_id = ?", listID).
+		Select("MAX(position)").
+		Scan(&maxPosition).Error; err != nil {
+		return nil, err
+	}
+
+	item = models.ReadingListItem{ReadingListID: listID, PostID: postID}
+	if maxPosition != nil {
+		item.Position = *maxPosition + 1
+	}
+
+	if err := config.DB.Create(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// BookmarkedPostIDs reports which of the given posts appear in any of the
+// user's reading lists.
+func BookmarkedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error) {
+	bookmarked := make(map[uint]bool)
+	if len(postIDs) == 0 {
+		return bookmarked, nil
+	}
+
+	var ids []uint
+	err := config.DB.Model(&models.ReadingListItem{}).
+		Distinct().
+		Joins("JOIN reading_lists ON reading_lists.id = reading_list_items.reading_list_id").
+		Where("reading_lists.user_id = ? AND reading_list_items.post_id IN ?", userID, postIDs).
+		Pluck("reading_list_items.post_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		bookmarked[id] = true
+	}
+	return bookmarked, nil
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"testing"
+)
+
+func TestDefaultReadingListIsCreatedOnce(t *testing.T) {
+	useTestDB(t, &models.User{}, &models.ReadingList{})
+
+	user := models.User{Name: "Reader", Username: "reader", Password: "x", RoleID: 2}
+	if err := config.DB.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := DefaultReadingList(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := DefaultReadingList(user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != first.ID {
+		t.Fatalf("second call returned list %d, want %d", again.ID, first.ID)
+	}
+
+	// A second default list, as a racing request would insert, is refused.
+	duplicate := models.ReadingList{UserID: user.ID, Name: "Other", IsDefault: true, DefaultFor: &user.ID}
+	if err := config.DB.Create(&duplicate).Error; err == nil {
+		t.Fatal("created a second default list")
+	}
+
+	// Other lists of the same user are unaffected.
+	for _, name := range []string{"Later", "Favourites"} {
+		if err := config.DB.Create(&models.ReadingList{UserID: user.ID, Name: name}).Error; err != nil {
+			t.Fatalf("creating %s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"backend/models"
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// Average silent reading speeds. CJK text is read per character;
+	// Vietnamese is written one syllable per space-separated token, and a
+	// word is usually two syllables, so it is counted faster per token.
+	wordsPerMinute               = 230
+	vietnameseSyllablesPerMinute = 320
+	cjkCharactersPerMinute       = 500
+
+	ExcerptLength = 200
+)
+
+// ReadingStats are derived from a post's content on every save.
+type ReadingStats struct {
+	WordCount      int
+	ReadingMinutes int
+	Excerpt        string
+}
+
+// Apply copies the stats onto the post.
+func (stats ReadingStats) Apply(post *models.Post) {
+	post.WordCount = stats.WordCount
+	post.ReadingMinutes = stats.ReadingMinutes
+	post.Excerpt = stats.Excerpt
+}
+
+// ComputeReadingStats counts the words in the rendered content and estimates
+// the reading time. The excerpt is the description when there is one,
+// otherwise the opening text of the post.
+func ComputeReadingStats(format, source, renderedHTML, description string) ReadingStats {
+	text := HTMLText(renderedHTML)
+	words, cjk := countWords(text)
+
+	minutes := 0
+	if words+cjk > 0 {
+		wpm := float64(wordsPerMinute)
+		if looksVietnamese(text) {
+			wpm = vietnameseSyllablesPerMinute
+		}
+		minutes = int(math.Ceil(float64(words)/wpm + float64(cjk)/cjkCharactersPerMinute))
+	}
+
+	excerpt := strings.TrimSpace(description)
+	if excerpt == "" && format == models.ContentFormatBlocks {
+		if doc, err := ParseBlockDocument(source); err == nil {
+			excerpt = doc.Excerpt(ExcerptLength)
+		}
+	}
+	if excerpt == "" {
+		excerpt = truncateWords(strings.Join(strings.Fields(text), " "), ExcerptLength)
+	}
+
+	return ReadingStats{WordCount: words + cjk, ReadingMinutes: minutes, Excerpt: excerpt}
+}
+
+// isCJK reports whether r is read as a word on its own: Han ideographs and
+// Japanese kana. Korean separates words with spaces and is counted like
+// Latin text.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana)
+}
+
+// countWords returns the number of space-separated words and, separately, the
+// number of CJK characters. A run such as "Go言語" counts as one word and two
+// characters.
+func countWords(text string) (words, cjk int) {
+	inWord := false
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r):
+			if !inWord {
+				words++
+				inWord = true
+			}
+		case r == '\'' || r == '’' || r == '-':
+			// part of the word: "don't", "e-mail"
+		default:
+			inWord = false
+		}
+	}
+	return words, cjk
+}
+
+// Letters only Vietnamese uses among Latin scripts.
+const vietnameseLetters = "ăâđêôơưạảấầẩẫậắằẳẵặẹẻẽếềểễệỉịọỏốồổỗộớờởỡợụủứừửữựỳỵỷỹ"
+
+// looksVietnamese reports whether at least a fifth of the words carry
+// Vietnamese-only letters; most syllables have a tone mark, but short ones
+// like "anh" or "em" do not.
+func looksVietnamese(text string) bool {
+	words := strings.Fields(strings.ToLower(norm.NFC.String(text)))
+	if len(words) == 0 {
+		return false
+	}
+
+	marked := 0
+	for _, word := range words {
+		if strings.ContainsAny(word, vietnameseLetters) {
+			marked++
+		}
+	}
+	return marked*5 >= len(words)
+}
+
+// Elements after which text continues on a new line.
+var blockElements = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "ul": true, "ol": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"pre": true, "blockquote": true, "figure": true, "figcaption": true,
+	"table": true, "tr": true, "td": true, "th": true, "hr": true,
+}
+
+// HTMLText returns the text of an HTML document with block elements on their
+// own lines, so words in adjacent paragraphs are not run together.
+func HTMLText(document string) string {
+	var b strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(document))
+	skip := 0
+
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			return strings.TrimSpace(b.String())
+		case html.TextToken:
+			if skip == 0 {
+				b.Write(tokenizer.Text())
+			}
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			tag := string(name)
+			if tag == "script" || tag == "style" {
+				if tokenType == html.StartTagToken {
+					skip++
+				} else if skip > 0 {
+					skip--
+				}
+			}
+			if blockElements[tag] {
+				b.WriteString("\n")
+			}
+		}
+	}
+}
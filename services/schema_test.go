@@ -0,0 +1,47 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"testing"
+)
+
+func TestDeletingAPostRemovesItsRows(t *testing.T) {
+	useTestDB(t, models.All()...)
+
+	author := models.User{Name: "Author", Username: "author", Password: "x", RoleID: 2}
+	reader := models.User{Name: "Reader", Username: "reader", Password: "x", RoleID: 2}
+	for _, user := range []*models.User{&author, &reader} {
+		if err := config.DB.Create(user).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	post := models.Post{Title: "Post", Content: "Body", UserID: author.ID}
+	if err := config.DB.Create(&post).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, row := range []interface{}{
+		&models.PostCollaborator{PostID: post.ID, UserID: reader.ID, Role: models.CollaboratorEditor, Status: models.InvitationAccepted, InvitedByID: author.ID},
+		&models.Clap{PostID: post.ID, UserID: reader.ID, Count: 3},
+	} {
+		if err := config.DB.Create(row).Error; err != nil {
+			t.Fatalf("creating %T: %v", row, err)
+		}
+	}
+
+	if err := config.DB.Delete(&post).Error; err != nil {
+		t.Fatalf("deleting the post: %v", err)
+	}
+
+	for _, model := range []interface{}{&models.PostCollaborator{}, &models.Clap{}} {
+		var count int64
+		if err := config.DB.Model(model).Where("post_id = ?", post.ID).Count(&count).Error; err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 {
+			t.Errorf("%d %T rows left after deleting the post", count, model)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"backend/models"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// Upper bounds of the SEO overrides, matching their columns on Post.
+const (
+	maxMetaTitle       = 120
+	maxMetaDescription = 320
+	maxSEOURL          = 512
+)
+
+// SEOFields are the per-post overrides an author can set. Empty fields fall
+// back to the post's own title, excerpt, URL and image.
+type SEOFields struct {
+	MetaTitle       string
+	MetaDescription string
+	CanonicalURL    string
+	OGImage         string
+}
+
+func (fields SEOFields) Validate() error {
+	if utf8.RuneCountInString(fields.MetaTitle) > maxMetaTitle {
+		return fmt.Errorf("meta_title must be at most %d characters", maxMetaTitle)
+	}
+	if utf8.RuneCountInString(fields.MetaDescription) > maxMetaDescription {
+		return fmt.Errorf("meta_description must be at most %d characters", maxMetaDescription)
+	}
+	if fields.CanonicalURL != "" && (!validWebURL(fields.CanonicalURL) || len(fields.CanonicalURL) > maxSEOURL) {
+		return fmt.Errorf("canonical_url must be an http(s) URL of at most %d characters", maxSEOURL)
+	}
+	if fields.OGImage != "" && (!validWebURL(fields.OGImage) || len(fields.OGImage) > maxSEOURL) {
+		return fmt.Errorf("og_image must be an http(s) URL of at most %d characters", maxSEOURL)
+	}
+	return nil
+}
+
+// Apply copies the fields onto the post.
+func (fields SEOFields) Apply(post *models.Post) {
+	post.MetaTitle = fields.MetaTitle
+	post.MetaDescription = fields.MetaDescription
+	post.CanonicalURL = fields.CanonicalURL
+	post.OGImage = fields.OGImage
+}
+
+// SEOMetadata is what a page needs for its <title>, meta description,
+// canonical link and Open Graph tags.
+type SEOMetadata struct {
+	Title         string    `json:"title"`
+	Description   string    `json:"description"`
+	CanonicalURL  string    `json:"canonical_url"`
+	Image         string    `json:"image"`
+	Type          string    `json:"type"`
+	PublishedTime time.Time `json:"published_time"`
+	ModifiedTime  time.Time `json:"modified_time"`
+	Tags          []string  `json:"tags"`
+}
+
+// PostURL is the public address of a post.
+func PostURL(siteURL string, postID uint) string {
+	return fmt.Sprintf("%s/posts/%d", siteURL, postID)
+}
+
+// PostSEO resolves the post's metadata, filling unset overrides from the
+// post itself.
+func PostSEO(post models.Post, siteURL string) SEOMetadata {
+	metadata := SEOMetadata{
+		Title:         post.MetaTitle,
+		Description:   post.MetaDescription,
+		CanonicalURL:  post.CanonicalURL,
+		Image:         post.OGImage,
+		Type:          "article",
+		PublishedTime: post.CreatedAt,
+		ModifiedTime:  post.UpdatedAt,
+		Tags:          []string{},
+	}
+	if metadata.Title == "" {
+		metadata.Title = post.Title
+	}
+	if metadata.Description == "" {
+		metadata.Description = truncateWords(post.Excerpt, 160)
+	}
+	if metadata.CanonicalURL == "" {
+		metadata.CanonicalURL = PostURL(siteURL, post.ID)
+	}
+	if metadata.Image == "" {
+		metadata.Image = post.Image
+	}
+	for _, tag := range post.Tags {
+		metadata.Tags = append(metadata.Tags, tag.Name)
+	}
+	return metadata
+}
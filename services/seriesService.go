@@ -0,0 +1,66 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+)
+
+type SeriesPostLink struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+}
+
+// SeriesNavigation places a post within its series: "part 2 of 5" plus links
+// to its neighbours.
+type SeriesNavigation struct {
+	ID       uint            `json:"id"`
+	Title    string          `json:"title"`
+	Slug     string          `json:"slug"`
+	Part     int             `json:"part"`
+	Total    int             `json:"total"`
+	Previous *SeriesPostLink `json:"previous"`
+	Next     *SeriesPostLink `json:"next"`
+}
+
+// SeriesPostOrder is the order in which a series lists its posts.
+const SeriesPostOrder = "series_position ASC, id ASC"
+
+// NavigateSeries returns the post's series navigation, or nil when the post
+// is not part of a series.
+func NavigateSeries(post models.Post) (*SeriesNavigation, error) {
+	if post.SeriesID == nil {
+		return nil, nil
+	}
+
+	var series models.Series
+	if err := config.DB.First(&series, *post.SeriesID).Error; err != nil {
+		return nil, err
+	}
+
+	var links []SeriesPostLink
+	if err := config.DB.Model(&models.Post{}).
+		Select("id, title").
+		Where("series_id = ?", series.ID).
+		Order(SeriesPostOrder).
+		Scan(&links).Error; err != nil {
+		return nil, err
+	}
+
+	navigation := &SeriesNavigation{ID: series.ID, Title: series.Title, Slug: series.Slug, Total: len(links)}
+	for i, link := range links {
+		if link.ID != post.ID {
+			continue
+		}
+
+		navigation.Part = i + 1
+		if i > 0 {
+			navigation.Previous = &links[i-1]
+		}
+		if i+1 < len(links) {
+			navigation.Next = &links[i+1]
+		}
+		break
+	}
+
+	return navigation, nil
+}
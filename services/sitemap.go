@@ -0,0 +1,78 @@
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SitemapMaxURLs is the most URLs one sitemap file may list; larger sites are
+// split into pages behind a sitemap index.
+const SitemapMaxURLs = 50000
+
+// SitemapURL is one <url> entry. LastModified is left out when zero.
+type SitemapURL struct {
+	Location     string
+	LastModified time.Time
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name         `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapElement `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name         `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+	Sitemaps []sitemapElement `xml:"sitemap"`
+}
+
+type sitemapElement struct {
+	Location     string `xml:"loc"`
+	LastModified string `xml:"lastmod,omitempty"`
+}
+
+func newSitemapElement(entry SitemapURL) sitemapElement {
+	element := sitemapElement{Location: entry.Location}
+	if !entry.LastModified.IsZero() {
+		element.LastModified = entry.LastModified.UTC().Format(time.RFC3339)
+	}
+	return element
+}
+
+// RenderSitemap renders a <urlset> of at most SitemapMaxURLs entries.
+func RenderSitemap(entries []SitemapURL) ([]byte, error) {
+	if len(entries) > SitemapMaxURLs {
+		return nil, fmt.Errorf("sitemap has %d URLs, at most %d are allowed", len(entries), SitemapMaxURLs)
+	}
+
+	urlSet := sitemapURLSet{URLs: make([]sitemapElement, 0, len(entries))}
+	for _, entry := range entries {
+		urlSet.URLs = append(urlSet.URLs, newSitemapElement(entry))
+	}
+	return marshalXML(urlSet)
+}
+
+// RenderSitemapIndex renders a <sitemapindex> pointing at sitemap pages.
+func RenderSitemapIndex(sitemaps []SitemapURL) ([]byte, error) {
+	index := sitemapIndex{Sitemaps: make([]sitemapElement, 0, len(sitemaps))}
+	for _, sitemap := range sitemaps {
+		index.Sitemaps = append(index.Sitemaps, newSitemapElement(sitemap))
+	}
+	return marshalXML(index)
+}
+
+// Paths crawlers are kept out of: API endpoints that only make sense for a
+// signed-in user.
+var robotsDisallow = []string{"/admin/", "/me/", "/notifications", "/invitations/", "/unfurl"}
+
+// RobotsTxt allows crawling everything public and points at the sitemap.
+func RobotsTxt(siteURL string) string {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	for _, path := range robotsDisallow {
+		fmt.Fprintf(&b, "Disallow: %s\n", path)
+	}
+	fmt.Fprintf(&b, "\nSitemap: %s/sitemap.xml\n", siteURL)
+	return b.String()
+}
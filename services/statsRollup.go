@@ -0,0 +1,280 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// How far back the first rollup run reaches.
+	statsBackfill = 90 * 24 * time.Hour
+	// Referrer host used for views without a Referer header.
+	DirectReferrer = "direct"
+)
+
+// StatsDay truncates t to the start of its UTC day.
+func StatsDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// UTCDay returns a SQL expression for the UTC calendar day of a timestamp
+// column. DATE(column) alone would use the session time zone.
+func UTCDay(column string) string {
+	switch config.DB.Dialector.Name() {
+	case "postgres":
+		return "DATE(" + column + " AT TIME ZONE 'UTC')"
+	case "mysql":
+		// DATETIME columns hold local wall-clock time (loc=Local in the DSN).
+		// Converting from the session zone rather than a fixed offset keeps
+		// rows on the other side of a DST change in the right day.
+		return "DATE(CONVERT_TZ(" + column + ", @@session.time_zone, '+00:00'))"
+	default:
+		return "DATE(" + column + ")"
+	}
+}
+
+// ParseStatsDay reads a day returned by UTCDay or a date column. Drivers
+// return "2006-01-02", a full timestamp string or a time, so only the date
+// prefix is used.
+func ParseStatsDay(value string) (time.Time, error) {
+	if len(value) < len("2006-01-02") {
+		return time.Time{}, fmt.Errorf("invalid day %q", value)
+	}
+	return time.Parse("2006-01-02", value[:len("2006-01-02")])
+}
+
+// ReferrerHost reduces a Referer header to its host, without "www.".
+func ReferrerHost(referrer string) string {
+	parsed, err := url.Parse(referrer)
+	if err != nil || parsed.Host == "" {
+		return DirectReferrer
+	}
+	return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+}
+
+type dailyCount struct {
+	PostID uint
+	Day    string
+	Total  int64
+}
+
+type postDayKey struct {
+	postID uint
+	day    time.Time
+}
+
+// dailyCounts groups rows of model created in [from, to) by post_id (or the
+// given key column) and day.
+func dailyCounts(model interface{}, keyColumn, total string, from, to time.Time) ([]dailyCount, error) {
+	dayExpr := UTCDay("created_at")
+
+	var counts []dailyCount
+	err := config.DB.Model(model).
+		Select(keyColumn+" AS post_id, "+dayExpr+" AS day, "+total+" AS total").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group(keyColumn + ", " + dayExpr).
+		Scan(&counts).Error
+	return counts, err
+}
+
+// RefreshStatsRollup rebuilds the daily rollups for the days in [from, to).
+func RefreshStatsRollup(from, to time.Time) error {
+	from, to = StatsDay(from), StatsDay(to)
+
+	postStats := make(map[postDayKey]*models.PostDailyStat)
+	postStat := func(count dailyCount) (*models.PostDailyStat, error) {
+		day, err := ParseStatsDay(count.Day)
+		if err != nil {
+			return nil, err
+		}
+		key := postDayKey{count.PostID, day}
+		if postStats[key] == nil {
+			postStats[key] = &models.PostDailyStat{PostID: count.PostID, Day: day}
+		}
+		return postStats[key], nil
+	}
+
+	signals := []struct {
+		model interface{}
+		total string
+		set   func(*models.PostDailyStat, int64)
+	}{
+		{&models.PostView{}, "COUNT(*)", func(stat *models.PostDailyStat, total int64) { stat.Views = total }},
+		{&models.PostRead{}, "COUNT(*)", func(stat *models.PostDailyStat, total int64) { stat.Reads = total }},
+		{&models.Clap{}, "SUM(count)", func(stat *models.PostDailyStat, total int64) { stat.Claps = total }},
+		{&models.Comment{}, "COUNT(*)", func(stat *models.PostDailyStat, total int64) { stat.Comments = total }},
+	}
+	for _, signal := range signals {
+		counts, err := dailyCounts(signal.model, "post_id", signal.total, from, to)
+		if err != nil {
+			return err
+		}
+		for _, count := range counts {
+			stat, err := postStat(count)
+			if err != nil {
+				return err
+			}
+			signal.set(stat, count.Total)
+		}
+	}
+
+	var referrerRows []struct {
+		PostID   uint
+		Day      string
+		Referrer string
+		Total    int64
+	}
+	dayExpr := UTCDay("created_at")
+	if err := config.DB.Model(&models.PostView{}).
+		Select("post_id, "+dayExpr+" AS day, referrer, COUNT(*) AS total").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group("post_id, " + dayExpr + ", referrer").
+		Scan(&referrerRows).Error; err != nil {
+		return err
+	}
+
+	type referrerKey struct {
+		postDayKey
+		host string
+	}
+	referrerStats := make(map[referrerKey]*models.ReferrerDailyStat)
+	for _, row := range referrerRows {
+		day, err := ParseStatsDay(row.Day)
+		if err != nil {
+			return err
+		}
+		key := referrerKey{postDayKey{row.PostID, day}, ReferrerHost(row.Referrer)}
+		if referrerStats[key] == nil {
+			referrerStats[key] = &models.ReferrerDailyStat{PostID: row.PostID, Day: day, Host: key.host}
+		}
+		referrerStats[key].Views += row.Total
+	}
+
+	followerCounts, err := dailyCounts(&models.Follow{}, "followee_id", "COUNT(*)", from, to)
+	if err != nil {
+		return err
+	}
+	authorStats := make([]models.AuthorDailyStat, 0, len(followerCounts))
+	for _, count := range followerCounts {
+		day, err := ParseStatsDay(count.Day)
+		if err != nil {
+			return err
+		}
+		authorStats = append(authorStats, models.AuthorDailyStat{AuthorID: count.PostID, Day: day, NewFollowers: count.Total})
+	}
+
+	// Rollups are keyed by the post's author so the dashboard never has to
+	// join back to posts.
+	postIDs := make([]uint, 0, len(postStats))
+	for key := range postStats {
+		postIDs = append(postIDs, key.postID)
+	}
+	var posts []models.Post
+	if err := config.DB.Select("id, user_id").Where("id IN ?", postIDs).Find(&posts).Error; err != nil {
+		return err
+	}
+	authorOf := make(map[uint]uint, len(posts))
+	for _, post := range posts {
+		authorOf[post.ID] = post.UserID
+	}
+
+	rows := make([]models.PostDailyStat, 0, len(postStats))
+	for key, stat := range postStats {
+		if authorID, ok := authorOf[key.postID]; ok {
+			stat.AuthorID = authorID
+			rows = append(rows, *stat)
+		}
+	}
+	referrers := make([]models.ReferrerDailyStat, 0, len(referrerStats))
+	for key, stat := range referrerStats {
+		if authorID, ok := authorOf[key.postID]; ok {
+			stat.AuthorID = authorID
+			referrers = append(referrers, *stat)
+		}
+	}
+
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		for _, model := range []interface{}{&models.PostDailyStat{}, &models.AuthorDailyStat{}, &models.ReferrerDailyStat{}} {
+			if err := tx.Where("day >= ? AND day < ?", from, to).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if len(rows) > 0 {
+			if err := tx.CreateInBatches(&rows, 200).Error; err != nil {
+				return err
+			}
+		}
+		if len(authorStats) > 0 {
+			if err := tx.CreateInBatches(&authorStats, 200).Error; err != nil {
+				return err
+			}
+		}
+		if len(referrers) > 0 {
+			if err := tx.CreateInBatches(&referrers, 200).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// refreshRecentStats rebuilds everything from the last rolled-up day (which
+// may have been partial) through today. A rolled-up day later than today
+// never moves the window forward past today.
+func refreshRecentStats(now time.Time) error {
+	var latest models.PostDailyStat
+	from := now.Add(-statsBackfill)
+	if err := config.DB.Order("day DESC").Limit(1).Find(&latest).Error; err != nil {
+		return err
+	}
+	if latest.ID != 0 {
+		from = latest.Day
+		if today := StatsDay(now); from.After(today) {
+			from = today
+		}
+	}
+
+	return RefreshStatsRollup(from, now.Add(24*time.Hour))
+}
+
+// StartStatsRollupJob refreshes the rollups now and then every
+// STATS_ROLLUP_INTERVAL (default 15m) until ctx is cancelled.
+func StartStatsRollupJob(ctx context.Context) error {
+	interval := 15 * time.Minute
+	if value := os.Getenv("STATS_ROLLUP_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid STATS_ROLLUP_INTERVAL: %w", err)
+		}
+		interval = parsed
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := refreshRecentStats(time.Now()); err != nil {
+				log.Println("Failed to refresh stats rollup:", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
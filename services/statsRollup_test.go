@@ -0,0 +1,107 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"testing"
+	"time"
+)
+
+func TestStatsDay(t *testing.T) {
+	east := time.FixedZone("UTC+7", 7*60*60)
+	west := time.FixedZone("UTC-5", -5*60*60)
+
+	for _, test := range []struct {
+		in   time.Time
+		want string
+	}{
+		{time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), "2026-03-10"},
+		{time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), "2026-03-10"},
+		{time.Date(2026, 3, 10, 23, 59, 59, 0, time.UTC), "2026-03-10"},
+		{time.Date(2026, 3, 10, 5, 0, 0, 0, east), "2026-03-09"},
+		{time.Date(2026, 3, 10, 7, 0, 0, 0, east), "2026-03-10"},
+		{time.Date(2026, 3, 10, 20, 0, 0, 0, west), "2026-03-11"},
+		{time.Date(2026, 12, 31, 22, 0, 0, 0, west), "2027-01-01"},
+	} {
+		got := StatsDay(test.in)
+		if got.Format("2006-01-02") != test.want || got.Location() != time.UTC || !got.Equal(got.Truncate(24*time.Hour)) {
+			t.Errorf("StatsDay(%s) = %s, want %s 00:00 UTC", test.in, got, test.want)
+		}
+	}
+}
+
+func TestParseStatsDay(t *testing.T) {
+	for value, want := range map[string]string{
+		"2026-03-10":                "2026-03-10",
+		"2026-03-10 00:00:00":       "2026-03-10",
+		"2026-03-10T00:00:00Z":      "2026-03-10",
+		"2026-03-10 00:00:00+07:00": "2026-03-10",
+	} {
+		got, err := ParseStatsDay(value)
+		if err != nil || got.Format("2006-01-02") != want || got.Location() != time.UTC {
+			t.Errorf("ParseStatsDay(%q) = %s, %v, want %s", value, got, err, want)
+		}
+	}
+
+	for _, value := range []string{"", "2026-03", "10/03/2026"} {
+		if _, err := ParseStatsDay(value); err == nil {
+			t.Errorf("ParseStatsDay(%q) succeeded", value)
+		}
+	}
+}
+
+func TestRefreshStatsRollupBucketsByUTCDay(t *testing.T) {
+	useTestDB(t, models.All()...)
+
+	author := models.User{Name: "Author", Username: "author", Password: "x", RoleID: 2}
+	if err := config.DB.Create(&author).Error; err != nil {
+		t.Fatal(err)
+	}
+	post := models.Post{Title: "Post", Content: "Body", UserID: author.ID}
+	if err := config.DB.Create(&post).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// Late evening and early morning east of UTC fall on the previous UTC day.
+	east := time.FixedZone("UTC+7", 7*60*60)
+	for _, view := range []models.PostView{
+		{CreatedAt: time.Date(2026, 3, 10, 23, 30, 0, 0, east), Referrer: "https://www.example.com/a"},
+		{CreatedAt: time.Date(2026, 3, 11, 1, 0, 0, 0, east), Referrer: "https://example.com/b"},
+		{CreatedAt: time.Date(2026, 3, 11, 8, 0, 0, 0, east)},
+		{CreatedAt: time.Date(2026, 3, 11, 23, 59, 0, 0, time.UTC)},
+	} {
+		view.PostID, view.VisitorID = post.ID, "visitor"
+		if err := config.DB.Create(&view).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	from := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	if err := RefreshStatsRollup(from, from.AddDate(0, 0, 5)); err != nil {
+		t.Fatal(err)
+	}
+
+	var stats []models.PostDailyStat
+	if err := config.DB.Order("day ASC").Find(&stats).Error; err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]int64)
+	for _, stat := range stats {
+		if stat.AuthorID != author.ID {
+			t.Errorf("%s rolled up for author %d, want %d", stat.Day, stat.AuthorID, author.ID)
+		}
+		got[stat.Day.UTC().Format("2006-01-02")] = stat.Views
+	}
+	want := map[string]int64{"2026-03-10": 2, "2026-03-11": 2}
+	if len(got) != len(want) || got["2026-03-10"] != want["2026-03-10"] || got["2026-03-11"] != want["2026-03-11"] {
+		t.Errorf("views per day = %v, want %v", got, want)
+	}
+
+	var referrers []models.ReferrerDailyStat
+	if err := config.DB.Where("host = ?", "example.com").Find(&referrers).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(referrers) != 1 || referrers[0].Views != 2 || referrers[0].Day.UTC().Format("2006-01-02") != "2026-03-10" {
+		t.Errorf("example.com referrers = %+v, want 2 views on 2026-03-10", referrers)
+	}
+}
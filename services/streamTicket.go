@@ -0,0 +1,81 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const StreamTicketTTL = 30 * time.Second
+
+var ErrInvalidStreamTicket = errors.New("invalid or expired stream ticket")
+
+func hashStreamTicket(ticket string) string {
+	sum := sha256.Sum256([]byte(ticket))
+	return hex.EncodeToString(sum[:])
+}
+
+// IssueStreamTicket creates a ticket the user can pass as ?ticket= to open an
+// EventSource or WebSocket. Unlike the JWT, it is harmless once it shows up
+// in an access log: it expires within seconds and works only once.
+func IssueStreamTicket(userID uint) (string, error) {
+	var user models.User
+	if err := config.DB.Select("id", "role").First(&user, userID).Error; err != nil {
+		return "", err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	ticket := hex.EncodeToString(secret)
+
+	// Expired tickets are never redeemed; clear them out as new ones are made.
+	if err := config.DB.Where("expires_at < ?", time.Now()).Delete(&models.StreamTicket{}).Error; err != nil {
+		return "", err
+	}
+
+	err := config.DB.Create(&models.StreamTicket{
+		TokenHash: hashStreamTicket(ticket),
+		UserID:    user.ID,
+		Role:      user.Role,
+		ExpiresAt: time.Now().Add(StreamTicketTTL),
+	}).Error
+	return ticket, err
+}
+
+// RedeemStreamTicket consumes the ticket and returns whom it was issued to.
+func RedeemStreamTicket(ticket string) (models.StreamTicket, error) {
+	var stored models.StreamTicket
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("token_hash = ?", hashStreamTicket(ticket)).First(&stored).Error; err != nil {
+			return err
+		}
+		// Deleting is what claims the ticket, so two concurrent redemptions
+		// cannot both succeed.
+		result := tx.Delete(&models.StreamTicket{}, stored.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidStreamTicket
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return stored, ErrInvalidStreamTicket
+	}
+	if err != nil {
+		return stored, err
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return stored, ErrInvalidStreamTicket
+	}
+	return stored, nil
+}
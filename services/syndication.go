@@ -0,0 +1,360 @@
+package services
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	FeedRSS  = "rss"
+	FeedAtom = "atom"
+	FeedJSON = "json"
+)
+
+// FeedContentTypes maps a feed format to the media type it is served with.
+var FeedContentTypes = map[string]string{
+	FeedRSS:  "application/rss+xml; charset=utf-8",
+	FeedAtom: "application/atom+xml; charset=utf-8",
+	FeedJSON: "application/feed+json; charset=utf-8",
+}
+
+// SyndicationFeed is a format-neutral feed; Render turns it into RSS 2.0,
+// Atom 1.0 or JSON Feed 1.1.
+type SyndicationFeed struct {
+	Title       string
+	Description string
+	Link        string // the HTML page the feed mirrors
+	FeedURL     string
+	Updated     time.Time
+	Items       []SyndicationItem
+}
+
+type SyndicationAuthor struct {
+	Name string
+	URL  string
+}
+
+type SyndicationItem struct {
+	ID         string
+	Title      string
+	Link       string
+	Summary    string
+	Content    string // full HTML, empty for excerpt-only feeds
+	Authors    []SyndicationAuthor
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+	Image      *Enclosure
+}
+
+// Enclosure is a file attached to an item. Length is in bytes and 0 when
+// unknown.
+type Enclosure struct {
+	URL    string
+	Type   string
+	Length int64
+}
+
+// ImageEnclosure guesses the media type of an image URL from its extension.
+func ImageEnclosure(imageURL string, length int64) *Enclosure {
+	mediaType := "image/jpeg"
+	if parsed, err := url.Parse(imageURL); err == nil {
+		if byExtension := mime.TypeByExtension(strings.ToLower(path.Ext(parsed.Path))); strings.HasPrefix(byExtension, "image/") {
+			mediaType = byExtension
+		}
+	}
+	return &Enclosure{URL: imageURL, Type: mediaType, Length: length}
+}
+
+// SiteURL is the public address of the site, used for links in feeds and
+// sitemaps. It comes from SITE_URL, falling back to the request's own origin.
+func SiteURL(r *http.Request) string {
+	if site := ConfiguredSiteURL(); site != "" {
+		return site
+	}
+	return RequestOrigin(r)
+}
+
+// ConfiguredSiteURL is SITE_URL without a trailing slash, or "" when unset.
+func ConfiguredSiteURL() string {
+	return strings.TrimRight(os.Getenv("SITE_URL"), "/")
+}
+
+// RequestOrigin is the scheme and host the request was made to, honouring
+// X-Forwarded-Proto from a reverse proxy.
+func RequestOrigin(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := r.Header.Get("X-Forwarded-Proto"); forwarded != "" {
+		scheme = strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	return scheme + "://" + r.Host
+}
+
+func (feed SyndicationFeed) Render(format string) ([]byte, error) {
+	switch format {
+	case FeedRSS:
+		return feed.rss()
+	case FeedAtom:
+		return feed.atom()
+	case FeedJSON:
+		return feed.jsonFeed()
+	}
+	return nil, fmt.Errorf("unknown feed format %q", format)
+}
+
+type cdata struct {
+	Text string `xml:",cdata"`
+}
+
+type rssDocument struct {
+	XMLName        xml.Name   `xml:"rss"`
+	Version        string     `xml:"version,attr"`
+	AtomNamespace  string     `xml:"xmlns:atom,attr"`
+	ContentModule  string     `xml:"xmlns:content,attr"`
+	DublinCoreTerm string     `xml:"xmlns:dc,attr"`
+	Channel        rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Generator     string    `xml:"generator"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssGUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	Description cdata         `xml:"description"`
+	Content     *cdata        `xml:"content:encoded,omitempty"`
+	Creator     string        `xml:"dc:creator,omitempty"`
+	Categories  []string      `xml:"category"`
+	PubDate     string        `xml:"pubDate"`
+	Enclosure   *rssEnclosure `xml:"enclosure"`
+}
+
+func (feed SyndicationFeed) rss() ([]byte, error) {
+	channel := rssChannel{
+		Title:       feed.Title,
+		Link:        feed.Link,
+		Description: feed.Description,
+		Generator:   "blog backend",
+		Self:        atomLink{Rel: "self", Type: "application/rss+xml", Href: feed.FeedURL},
+	}
+	if !feed.Updated.IsZero() {
+		channel.LastBuildDate = feed.Updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, item := range feed.Items {
+		var creators []string
+		for _, author := range item.Authors {
+			creators = append(creators, author.Name)
+		}
+
+		entry := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{IsPermaLink: "true", Value: item.Link},
+			Description: cdata{item.Summary},
+			Creator:     strings.Join(creators, ", "),
+			Categories:  item.Categories,
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+		}
+		if item.Content != "" {
+			entry.Content = &cdata{item.Content}
+		}
+		if item.Image != nil {
+			entry.Enclosure = &rssEnclosure{URL: item.Image.URL, Length: item.Image.Length, Type: item.Image.Type}
+		}
+		channel.Items = append(channel.Items, entry)
+	}
+
+	return marshalXML(rssDocument{
+		Version:        "2.0",
+		AtomNamespace:  "http://www.w3.org/2005/Atom",
+		ContentModule:  "http://purl.org/rss/1.0/modules/content/",
+		DublinCoreTerm: "http://purl.org/dc/elements/1.1/",
+		Channel:        channel,
+	})
+}
+
+type atomLink struct {
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Href   string `xml:"href,attr"`
+	Length int64  `xml:"length,attr,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomDocument struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Authors    []atomPerson   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+}
+
+func (feed SyndicationFeed) atom() ([]byte, error) {
+	document := atomDocument{
+		ID:       feed.FeedURL,
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		Updated:  feed.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "alternate", Type: "text/html", Href: feed.Link},
+			{Rel: "self", Type: "application/atom+xml", Href: feed.FeedURL},
+		},
+	}
+
+	for _, item := range feed.Items {
+		entry := atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Links:     []atomLink{{Rel: "alternate", Type: "text/html", Href: item.Link}},
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   item.Updated.UTC().Format(time.RFC3339),
+		}
+		if item.Image != nil {
+			entry.Links = append(entry.Links, atomLink{Rel: "enclosure", Type: item.Image.Type, Href: item.Image.URL, Length: item.Image.Length})
+		}
+		for _, author := range item.Authors {
+			entry.Authors = append(entry.Authors, atomPerson{Name: author.Name, URI: author.URL})
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		if item.Summary != "" {
+			entry.Summary = &atomText{Type: "text", Value: item.Summary}
+		}
+		if item.Content != "" {
+			entry.Content = &atomText{Type: "html", Value: item.Content}
+		}
+		document.Entries = append(document.Entries, entry)
+	}
+
+	return marshalXML(document)
+}
+
+func marshalXML(document interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type jsonFeedAttachment struct {
+	URL         string `json:"url"`
+	MimeType    string `json:"mime_type"`
+	SizeInBytes int64  `json:"size_in_bytes,omitempty"`
+}
+
+type jsonFeedItem struct {
+	ID            string               `json:"id"`
+	URL           string               `json:"url"`
+	Title         string               `json:"title"`
+	ContentHTML   string               `json:"content_html,omitempty"`
+	ContentText   string               `json:"content_text,omitempty"`
+	Summary       string               `json:"summary,omitempty"`
+	Image         string               `json:"image,omitempty"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Authors       []jsonFeedAuthor     `json:"authors,omitempty"`
+	Tags          []string             `json:"tags,omitempty"`
+	Attachments   []jsonFeedAttachment `json:"attachments,omitempty"`
+}
+
+func (feed SyndicationFeed) jsonFeed() ([]byte, error) {
+	items := make([]jsonFeedItem, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		entry := jsonFeedItem{
+			ID:            item.ID,
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentHTML:   item.Content,
+			Summary:       item.Summary,
+			DatePublished: item.Published.UTC().Format(time.RFC3339),
+			DateModified:  item.Updated.UTC().Format(time.RFC3339),
+			Tags:          item.Categories,
+		}
+		// An item needs content_html or content_text
+		if entry.ContentHTML == "" {
+			entry.ContentText = item.Summary
+		}
+		for _, author := range item.Authors {
+			entry.Authors = append(entry.Authors, jsonFeedAuthor{Name: author.Name, URL: author.URL})
+		}
+		if item.Image != nil {
+			entry.Image = item.Image.URL
+			entry.Attachments = []jsonFeedAttachment{{URL: item.Image.URL, MimeType: item.Image.Type, SizeInBytes: item.Image.Length}}
+		}
+		items = append(items, entry)
+	}
+
+	return json.MarshalIndent(map[string]interface{}{
+		"version":       "https://jsonfeed.org/version/1.1",
+		"title":         feed.Title,
+		"description":   feed.Description,
+		"home_page_url": feed.Link,
+		"feed_url":      feed.FeedURL,
+		"items":         items,
+	}, "", "  ")
+}
@@ -0,0 +1,290 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() SyndicationFeed {
+	published := time.Date(2026, 3, 10, 9, 30, 0, 0, time.FixedZone("UTC+7", 7*60*60))
+	return SyndicationFeed{
+		Title:       "Blog & friends",
+		Description: "Posts <new>",
+		Link:        "https://blog.example/",
+		FeedURL:     "https://blog.example/feed.xml",
+		Updated:     published.Add(time.Hour),
+		Items: []SyndicationItem{
+			{
+				ID:         "https://blog.example/posts/1",
+				Title:      "First <post>",
+				Link:       "https://blog.example/posts/1",
+				Summary:    "A summary with <b>markup</b> & a ]]> sequence",
+				Content:    "<p>Body with <a href=\"https://x.example/?a=1&b=2\">a link</a> and ]]> inside</p>",
+				Authors:    []SyndicationAuthor{{Name: "Ana", URL: "https://blog.example/u/ana"}, {Name: "Bao"}},
+				Categories: []string{"go", "web"},
+				Published:  published,
+				Updated:    published.Add(30 * time.Minute),
+				Image:      ImageEnclosure("https://img.example/cover.PNG?size=large", 2048),
+			},
+			{
+				ID:        "https://blog.example/posts/2",
+				Title:     "Excerpt only",
+				Link:      "https://blog.example/posts/2",
+				Summary:   "Just the excerpt",
+				Published: published.Add(-24 * time.Hour),
+				Updated:   published.Add(-24 * time.Hour),
+			},
+		},
+	}
+}
+
+func TestRenderRSS(t *testing.T) {
+	body, err := testFeed().Render(FeedRSS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(body, []byte(xml.Header)) {
+		t.Error("RSS is missing the XML declaration")
+	}
+	if !bytes.Contains(body, []byte("<description><![CDATA[")) {
+		t.Error("item descriptions are not wrapped in CDATA")
+	}
+
+	var rss struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title         string `xml:"title"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Self          struct {
+				Rel  string `xml:"rel,attr"`
+				Href string `xml:"href,attr"`
+			} `xml:"http://www.w3.org/2005/Atom link"`
+			Items []struct {
+				Title       string   `xml:"title"`
+				GUID        string   `xml:"guid"`
+				Description string   `xml:"description"`
+				Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+				Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+				Categories  []string `xml:"category"`
+				PubDate     string   `xml:"pubDate"`
+				Enclosure   *struct {
+					URL    string `xml:"url,attr"`
+					Length int64  `xml:"length,attr"`
+					Type   string `xml:"type,attr"`
+				} `xml:"enclosure"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(body, &rss); err != nil {
+		t.Fatalf("invalid RSS: %v\n%s", err, body)
+	}
+
+	feed := testFeed()
+	if rss.Version != "2.0" || rss.Channel.Title != feed.Title {
+		t.Errorf("version %q, title %q", rss.Version, rss.Channel.Title)
+	}
+	if rss.Channel.Self.Rel != "self" || rss.Channel.Self.Href != feed.FeedURL {
+		t.Errorf("self link = %+v", rss.Channel.Self)
+	}
+	if rss.Channel.LastBuildDate != "Tue, 10 Mar 2026 03:30:00 +0000" {
+		t.Errorf("lastBuildDate = %q, want RFC 1123 in UTC", rss.Channel.LastBuildDate)
+	}
+	if len(rss.Channel.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(rss.Channel.Items))
+	}
+
+	first, second := rss.Channel.Items[0], rss.Channel.Items[1]
+	if first.Title != "First <post>" || first.GUID != feed.Items[0].Link {
+		t.Errorf("title %q, guid %q", first.Title, first.GUID)
+	}
+	if first.Description != feed.Items[0].Summary || first.Content != feed.Items[0].Content {
+		t.Errorf("CDATA did not round-trip:\n%q\n%q", first.Description, first.Content)
+	}
+	if first.Creator != "Ana, Bao" || strings.Join(first.Categories, ",") != "go,web" {
+		t.Errorf("creator %q, categories %q", first.Creator, first.Categories)
+	}
+	if first.PubDate != "Tue, 10 Mar 2026 02:30:00 +0000" {
+		t.Errorf("pubDate = %q", first.PubDate)
+	}
+	if first.Enclosure == nil || first.Enclosure.URL != feed.Items[0].Image.URL ||
+		first.Enclosure.Type != "image/png" || first.Enclosure.Length != 2048 {
+		t.Errorf("enclosure = %+v", first.Enclosure)
+	}
+	if second.Content != "" || second.Enclosure != nil {
+		t.Errorf("excerpt-only item has content %q and enclosure %+v", second.Content, second.Enclosure)
+	}
+}
+
+func TestRenderAtom(t *testing.T) {
+	body, err := testFeed().Render(FeedAtom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var atom struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Updated string   `xml:"updated"`
+		Links   []struct {
+			Rel  string `xml:"rel,attr"`
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Links   []struct {
+				Rel    string `xml:"rel,attr"`
+				Type   string `xml:"type,attr"`
+				Href   string `xml:"href,attr"`
+				Length int64  `xml:"length,attr"`
+			} `xml:"link"`
+			Authors []struct {
+				Name string `xml:"name"`
+				URI  string `xml:"uri"`
+			} `xml:"author"`
+			Categories []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+			Summary *struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"summary"`
+			Content *struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(body, &atom); err != nil {
+		t.Fatalf("invalid Atom: %v\n%s", err, body)
+	}
+
+	feed := testFeed()
+	if atom.ID != feed.FeedURL || atom.Updated != "2026-03-10T03:30:00Z" {
+		t.Errorf("id %q, updated %q", atom.ID, atom.Updated)
+	}
+	if len(atom.Links) != 2 || atom.Links[0].Rel != "alternate" || atom.Links[1].Rel != "self" {
+		t.Errorf("feed links = %+v", atom.Links)
+	}
+	if len(atom.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(atom.Entries))
+	}
+
+	first, second := atom.Entries[0], atom.Entries[1]
+	if first.ID != feed.Items[0].ID || first.Updated != "2026-03-10T03:00:00Z" {
+		t.Errorf("entry id %q, updated %q", first.ID, first.Updated)
+	}
+	if len(first.Links) != 2 || first.Links[1].Rel != "enclosure" || first.Links[1].Type != "image/png" || first.Links[1].Length != 2048 {
+		t.Errorf("entry links = %+v", first.Links)
+	}
+	if len(first.Authors) != 2 || first.Authors[0].URI != "https://blog.example/u/ana" || first.Authors[1].URI != "" {
+		t.Errorf("authors = %+v", first.Authors)
+	}
+	if len(first.Categories) != 2 || first.Categories[1].Term != "web" {
+		t.Errorf("categories = %+v", first.Categories)
+	}
+	if first.Summary == nil || first.Summary.Type != "text" || first.Summary.Value != feed.Items[0].Summary {
+		t.Errorf("summary = %+v", first.Summary)
+	}
+	if first.Content == nil || first.Content.Type != "html" || first.Content.Value != feed.Items[0].Content {
+		t.Errorf("content = %+v", first.Content)
+	}
+	if second.Content != nil || len(second.Links) != 1 {
+		t.Errorf("excerpt-only entry has content %+v and links %+v", second.Content, second.Links)
+	}
+}
+
+func TestRenderJSONFeed(t *testing.T) {
+	body, err := testFeed().Render(FeedJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var feed struct {
+		Version string `json:"version"`
+		FeedURL string `json:"feed_url"`
+		Items   []struct {
+			ID          string `json:"id"`
+			ContentHTML string `json:"content_html"`
+			ContentText string `json:"content_text"`
+			Image       string `json:"image"`
+			Published   string `json:"date_published"`
+			Authors     []struct {
+				Name string `json:"name"`
+			} `json:"authors"`
+			Attachments []struct {
+				URL      string `json:"url"`
+				MimeType string `json:"mime_type"`
+				Size     int64  `json:"size_in_bytes"`
+			} `json:"attachments"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(body, &feed); err != nil {
+		t.Fatalf("invalid JSON Feed: %v", err)
+	}
+
+	source := testFeed()
+	if feed.Version != "https://jsonfeed.org/version/1.1" || feed.FeedURL != source.FeedURL || len(feed.Items) != 2 {
+		t.Fatalf("version %q, feed_url %q, %d items", feed.Version, feed.FeedURL, len(feed.Items))
+	}
+
+	first, second := feed.Items[0], feed.Items[1]
+	if first.ContentHTML != source.Items[0].Content || first.ContentText != "" {
+		t.Errorf("content_html %q, content_text %q", first.ContentHTML, first.ContentText)
+	}
+	if first.Published != "2026-03-10T02:30:00Z" || len(first.Authors) != 2 {
+		t.Errorf("date_published %q, authors %+v", first.Published, first.Authors)
+	}
+	if first.Image != source.Items[0].Image.URL || len(first.Attachments) != 1 ||
+		first.Attachments[0].MimeType != "image/png" || first.Attachments[0].Size != 2048 {
+		t.Errorf("image %q, attachments %+v", first.Image, first.Attachments)
+	}
+	// Every item needs content_html or content_text.
+	if second.ContentHTML != "" || second.ContentText != "Just the excerpt" {
+		t.Errorf("excerpt-only item: content_html %q, content_text %q", second.ContentHTML, second.ContentText)
+	}
+}
+
+func TestRenderEmptyFeedAndUnknownFormat(t *testing.T) {
+	empty := SyndicationFeed{Title: "Empty", Link: "https://blog.example/", FeedURL: "https://blog.example/feed.json"}
+	for _, format := range []string{FeedRSS, FeedAtom} {
+		body, err := empty.Render(format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := xml.Unmarshal(body, new(struct{})); err != nil {
+			t.Errorf("%s: invalid XML: %v", format, err)
+		}
+	}
+
+	body, err := empty.Render(FeedJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(body, []byte(`"items": []`)) {
+		t.Errorf("JSON Feed without items must still have an items array:\n%s", body)
+	}
+
+	if _, err := empty.Render("opml"); err == nil {
+		t.Error("rendered an unknown format")
+	}
+}
+
+func TestImageEnclosure(t *testing.T) {
+	for imageURL, want := range map[string]string{
+		"https://img.example/a.png":         "image/png",
+		"https://img.example/a.JPG":         "image/jpeg",
+		"https://img.example/a.webp?w=1200": "image/webp",
+		"https://img.example/a.gif#frag":    "image/gif",
+		"https://img.example/photo":         "image/jpeg",
+		"https://img.example/file.pdf":      "image/jpeg",
+	} {
+		if got := ImageEnclosure(imageURL, 0).Type; got != want {
+			t.Errorf("ImageEnclosure(%q).Type = %q, want %q", imageURL, got, want)
+		}
+	}
+}
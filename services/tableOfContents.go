@@ -0,0 +1,235 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// TOCEntry is a heading in a post's table of contents. Headings nest under
+// the closest preceding heading of a higher level.
+type TOCEntry struct {
+	ID       string      `json:"id"`
+	Text     string      `json:"text"`
+	Level    int         `json:"level"`
+	Children []*TOCEntry `json:"children"`
+}
+
+var headingLevels = map[atom.Atom]int{
+	atom.H1: 1, atom.H2: 2, atom.H3: 3, atom.H4: 4, atom.H5: 5, atom.H6: 6,
+}
+
+// foldMarks strips diacritics: "Lập trình" becomes "Lap trinh". Only the
+// combining diacritical marks block is removed, so marks that are part of
+// other scripts, such as the Japanese voicing marks, stay.
+var foldMarks = transform.Chain(norm.NFD, runes.Remove(runes.In(&unicode.RangeTable{
+	R16: []unicode.Range16{{Lo: 0x0300, Hi: 0x036f, Stride: 1}},
+})), norm.NFC)
+
+// AnchorSlug turns heading text into an anchor: lowercase letters and digits
+// joined by hyphens. Diacritics are dropped, other scripts such as CJK are
+// kept as they are.
+func AnchorSlug(text string) string {
+	folded, _, err := transform.String(foldMarks, text)
+	if err != nil {
+		folded = text
+	}
+	folded = strings.NewReplacer("đ", "d", "Đ", "d").Replace(folded)
+
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(folded) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+		} else {
+			hyphen = true
+		}
+	}
+
+	slug := b.String()
+	if slug == "" {
+		slug = "section"
+	}
+	return slug
+}
+
+// headingNodes returns the h1-h6 elements of a fragment in document order.
+func headingNodes(nodes []*html.Node) []*html.Node {
+	var headings []*html.Node
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode && headingLevels[node.DataAtom] > 0 {
+			headings = append(headings, node)
+			return
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	for _, node := range nodes {
+		walk(node)
+	}
+	return headings
+}
+
+// elementIDs returns the id attributes in a fragment, except those of the
+// given headings, which AnchorHeadings replaces.
+func elementIDs(nodes []*html.Node, headings []*html.Node) map[string]bool {
+	skip := make(map[*html.Node]bool, len(headings))
+	for _, heading := range headings {
+		skip[heading] = true
+	}
+
+	ids := make(map[string]bool)
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode && !skip[node] {
+			for _, attr := range node.Attr {
+				if attr.Key == "id" {
+					ids[attr.Val] = true
+				}
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	for _, node := range nodes {
+		walk(node)
+	}
+	return ids
+}
+
+func nodeText(node *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.TextNode {
+			b.WriteString(node.Data)
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(node)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func parseFragment(document string) ([]*html.Node, error) {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	return html.ParseFragment(strings.NewReader(document), body)
+}
+
+// AnchorHeadings gives every heading an id derived from its text, so the
+// same heading keeps its anchor across edits. A heading whose id is already
+// taken, by an earlier heading or any other element, gets "-2", "-3" and so
+// on. The HTML is returned unchanged if it cannot be parsed.
+func AnchorHeadings(document string) string {
+	nodes, err := parseFragment(document)
+	if err != nil {
+		log.Println("Failed to parse content for anchors:", err)
+		return document
+	}
+
+	headings := headingNodes(nodes)
+	if len(headings) == 0 {
+		return document
+	}
+
+	// Ids the author put on other elements stay, so headings must avoid them.
+	used := elementIDs(nodes, headings)
+	for _, heading := range headings {
+		base := AnchorSlug(nodeText(heading))
+		id := base
+		for n := 2; used[id]; n++ {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[id] = true
+
+		attrs := heading.Attr[:0]
+		for _, attr := range heading.Attr {
+			if attr.Key != "id" {
+				attrs = append(attrs, attr)
+			}
+		}
+		heading.Attr = append(attrs, html.Attribute{Key: "id", Val: id})
+	}
+
+	var b strings.Builder
+	for _, node := range nodes {
+		if err := html.Render(&b, node); err != nil {
+			log.Println("Failed to render anchored content:", err)
+			return document
+		}
+	}
+	return b.String()
+}
+
+// TableOfContents builds the nested outline of rendered content whose
+// headings went through AnchorHeadings, encoded as JSON for storage on the
+// post.
+func TableOfContents(renderedHTML string) string {
+	entries := []*TOCEntry{}
+
+	nodes, err := parseFragment(renderedHTML)
+	if err != nil {
+		log.Println("Failed to parse content for table of contents:", err)
+	}
+
+	var stack []*TOCEntry
+	for _, heading := range headingNodes(nodes) {
+		entry := &TOCEntry{Text: nodeText(heading), Level: headingLevels[heading.DataAtom], Children: []*TOCEntry{}}
+		for _, attr := range heading.Attr {
+			if attr.Key == "id" {
+				entry.ID = attr.Val
+			}
+		}
+		if entry.ID == "" || entry.Text == "" {
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].Level >= entry.Level {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			entries = append(entries, entry)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, entry)
+		}
+		stack = append(stack, entry)
+	}
+
+	encoded, err := json.Marshal(entries)
+	if err != nil {
+		log.Println("Failed to encode table of contents:", err)
+		return "[]"
+	}
+	return string(encoded)
+}
+
+// ParseTableOfContents decodes a table of contents stored by
+// TableOfContents. Posts saved before it existed have an empty one.
+func ParseTableOfContents(stored string) []*TOCEntry {
+	entries := []*TOCEntry{}
+	if stored == "" {
+		return entries
+	}
+	if err := json.Unmarshal([]byte(stored), &entries); err != nil {
+		log.Println("Failed to decode table of contents:", err)
+		return []*TOCEntry{}
+	}
+	return entries
+}
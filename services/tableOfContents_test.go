@@ -0,0 +1,105 @@
+package services
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+var idAttr = regexp.MustCompile(`<h\d[^>]* id="([^"]*)"`)
+
+func headingIDs(document string) []string {
+	var ids []string
+	for _, match := range idAttr.FindAllStringSubmatch(document, -1) {
+		ids = append(ids, match[1])
+	}
+	return ids
+}
+
+func TestAnchorSlug(t *testing.T) {
+	for text, want := range map[string]string{
+		"Getting Started":      "getting-started",
+		"  What's new?  ":      "what-s-new",
+		"Lập trình Go":         "lap-trinh-go",
+		"Đường đi":             "duong-di",
+		"Café & crème brûlée":  "cafe-creme-brulee",
+		"入門ガイド":                "入門ガイド",
+		"Step 2: configure":    "step-2-configure",
+		"!!!":                  "section",
+		"":                     "section",
+		"C++ -- the good bits": "c-the-good-bits",
+	} {
+		if got := AnchorSlug(text); got != want {
+			t.Errorf("AnchorSlug(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestAnchorHeadingsKeepsIDsUnique(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		document string
+		want     []string
+	}{
+		{"distinct headings", `<h2>One</h2><h2>Two</h2>`, []string{"one", "two"}},
+		{"repeated heading", `<h2>Setup</h2><h3>Setup</h3><h2>Setup</h2>`, []string{"setup", "setup-2", "setup-3"}},
+		{"numbered heading taken first", `<h2>Intro 2</h2><h2>Intro</h2><h2>Intro</h2>`, []string{"intro-2", "intro", "intro-3"}},
+		{"id of another element", `<p id="notes">x</p><h2>Notes</h2>`, []string{"notes-2"}},
+		{"author id on a heading is replaced", `<h2 id="custom">Usage</h2><h2>Custom</h2>`, []string{"usage", "custom"}},
+		{"headings without letters", `<h2>?</h2><h2>!</h2>`, []string{"section", "section-2"}},
+		{"nested heading", `<div><h2>Inside</h2></div><h2>Inside</h2>`, []string{"inside", "inside-2"}},
+		{"same text after folding", `<h2>Lập trình</h2><h2>Lap trinh</h2>`, []string{"lap-trinh", "lap-trinh-2"}},
+	} {
+		got := headingIDs(AnchorHeadings(test.document))
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: ids = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestAnchorHeadingsIsStableAcrossRenders(t *testing.T) {
+	document := `<h2>Setup</h2><p>text</p><h2>Setup</h2>`
+	once := AnchorHeadings(document)
+	if twice := AnchorHeadings(once); twice != once {
+		t.Errorf("anchoring twice changed the ids:\n%s\n%s", once, twice)
+	}
+	if document := "<p>No headings</p>"; AnchorHeadings(document) != document {
+		t.Errorf("content without headings was rewritten")
+	}
+}
+
+func TestTableOfContentsNestsHeadings(t *testing.T) {
+	stored := TableOfContents(AnchorHeadings(`<h2>Intro</h2><h3>Why</h3><h3>Why</h3><h4>Deep</h4><h2>Usage</h2><h1>Top</h1><h3>Loose</h3>`))
+
+	var entries []*TOCEntry
+	if err := json.Unmarshal([]byte(stored), &entries); err != nil {
+		t.Fatal(err)
+	}
+
+	type outline struct {
+		ID       string
+		Children []outline
+	}
+	var flatten func([]*TOCEntry) []outline
+	flatten = func(entries []*TOCEntry) []outline {
+		var result []outline
+		for _, entry := range entries {
+			result = append(result, outline{entry.ID, flatten(entry.Children)})
+		}
+		return result
+	}
+
+	want := []outline{
+		{"intro", []outline{{"why", nil}, {"why-2", []outline{{"deep", nil}}}}},
+		{"usage", nil},
+		{"top", []outline{{"loose", nil}}},
+	}
+	if got := flatten(entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("outline = %+v, want %+v", got, want)
+	}
+
+	if got := ParseTableOfContents(""); len(got) != 0 {
+		t.Errorf("empty table of contents = %v", got)
+	}
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"backend/cache"
+	config "backend/configs"
+	"backend/models"
+	"context"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TrendingWindows maps each supported window to how far back it looks.
+var TrendingWindows = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+}
+
+const (
+	trendingClapWeight     = 1.0
+	trendingCommentWeight  = 3.0
+	trendingBookmarkWeight = 4.0
+	trendingViewWeight     = 0.1
+	// How strongly the score is pulled down as a post ages.
+	trendingGravity = 1.5
+	// Posts ranked per window, overall and within each tag.
+	trendingLimit = 500
+	// Prefix of the cached trending listings, cleared after every refresh.
+	TrendingCachePrefix = "posts:trending:"
+)
+
+// ComputeTrendingScore weighs the engagement a post received inside the window and
+// divides it by its age in hours, so fresh posts with a little traction beat
+// old posts with slightly more. Age is capped at the window length: every
+// post older than the window is judged on its engagement alone.
+func ComputeTrendingScore(engagement models.TrendingScore, createdAt time.Time, window time.Duration, now time.Time) float64 {
+	weighted := trendingClapWeight*float64(engagement.Claps) +
+		trendingCommentWeight*float64(engagement.Comments) +
+		trendingBookmarkWeight*float64(engagement.Bookmarks) +
+		trendingViewWeight*float64(engagement.Views)
+
+	age := now.Sub(createdAt)
+	if age < 0 {
+		age = 0
+	}
+	if age > window {
+		age = window
+	}
+
+	return weighted / math.Pow(age.Hours()+2, trendingGravity)
+}
+
+type postCount struct {
+	PostID uint
+	Total  int64
+}
+
+// RefreshTrending recomputes and stores the ranking for one window.
+func RefreshTrending(windowName string, now time.Time) error {
+	window := TrendingWindows[windowName]
+	since := now.Add(-window)
+
+	engagement := make(map[uint]*models.TrendingScore)
+	entry := func(postID uint) *models.TrendingScore {
+		if engagement[postID] == nil {
+			engagement[postID] = &models.TrendingScore{Window: windowName, PostID: postID, ComputedAt: now}
+		}
+		return engagement[postID]
+	}
+
+	signals := []struct {
+		query *gorm.DB
+		add   func(*models.TrendingScore, int64)
+	}{
+		{
+			config.DB.Model(&models.Clap{}).Select("post_id, SUM(count) AS total"),
+			func(score *models.TrendingScore, total int64) { score.Claps = total },
+		},
+		{
+			config.DB.Model(&models.Comment{}).Select("post_id, COUNT(*) AS total"),
+			func(score *models.TrendingScore, total int64) { score.Comments = total },
+		},
+		{
+			config.DB.Model(&models.ReadingListItem{}).Select("post_id, COUNT(*) AS total"),
+			func(score *models.TrendingScore, total int64) { score.Bookmarks = total },
+		},
+		{
+			config.DB.Model(&models.PostView{}).Select("post_id, COUNT(*) AS total"),
+			func(score *models.TrendingScore, total int64) { score.Views = total },
+		},
+	}
+	for _, signal := range signals {
+		var counts []postCount
+		if err := signal.query.Where("created_at >= ?", since).Group("post_id").Scan(&counts).Error; err != nil {
+			return err
+		}
+		for _, count := range counts {
+			signal.add(entry(count.PostID), count.Total)
+		}
+	}
+
+	postIDs := make([]uint, 0, len(engagement))
+	for postID := range engagement {
+		postIDs = append(postIDs, postID)
+	}
+
+	var posts []models.Post
+	if err := config.DB.Select("id, created_at").Where("id IN ?", postIDs).Find(&posts).Error; err != nil {
+		return err
+	}
+
+	scores := make([]models.TrendingScore, 0, len(posts))
+	for _, post := range posts {
+		score := engagement[post.ID]
+		score.Score = ComputeTrendingScore(*score, post.CreatedAt, window, now)
+		scores = append(scores, *score)
+	}
+
+	sort.Slice(scores, func(i, j int) bool { return scores[i].Score > scores[j].Score })
+
+	scores, err := topTrending(scores)
+	if err != nil {
+		return err
+	}
+
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("time_window = ?", windowName).Delete(&models.TrendingScore{}).Error; err != nil {
+			return err
+		}
+		if len(scores) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(&scores, 100).Error
+	})
+}
+
+// topTrending keeps the first trendingLimit scores overall and, so that
+// per-tag rankings are not cut short by posts outside the tag, the first
+// trendingLimit within each tag. scores must be sorted best first.
+func topTrending(scores []models.TrendingScore) ([]models.TrendingScore, error) {
+	postIDs := make([]uint, 0, len(scores))
+	for _, score := range scores {
+		postIDs = append(postIDs, score.PostID)
+	}
+
+	var postTags []struct {
+		PostID uint
+		TagID  uint
+	}
+	if err := config.DB.Table("post_tags").Select("post_id, tag_id").Where("post_id IN ?", postIDs).Scan(&postTags).Error; err != nil {
+		return nil, err
+	}
+	tagsByPost := make(map[uint][]uint)
+	for _, postTag := range postTags {
+		tagsByPost[postTag.PostID] = append(tagsByPost[postTag.PostID], postTag.TagID)
+	}
+
+	kept := make([]models.TrendingScore, 0, min(len(scores), trendingLimit))
+	rankedInTag := make(map[uint]int)
+	for rank, score := range scores {
+		keep := rank < trendingLimit
+		for _, tagID := range tagsByPost[score.PostID] {
+			if rankedInTag[tagID] < trendingLimit {
+				rankedInTag[tagID]++
+				keep = true
+			}
+		}
+		if keep {
+			kept = append(kept, score)
+		}
+	}
+
+	return kept, nil
+}
+
+// StartTrendingJob refreshes every window now and then every
+// TRENDING_INTERVAL (default 10m) until ctx is cancelled.
+func StartTrendingJob(ctx context.Context) error {
+	interval := 10 * time.Minute
+	if value := os.Getenv("TRENDING_INTERVAL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		interval = parsed
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			refreshAllTrending(ctx)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+func refreshAllTrending(ctx context.Context) {
+	now := time.Now()
+	for windowName := range TrendingWindows {
+		if err := RefreshTrending(windowName, now); err != nil {
+			log.Printf("Failed to refresh %s trending: %v", windowName, err)
+		}
+	}
+	cache.Invalidate(TrendingCachePrefix)
+}
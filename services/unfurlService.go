@@ -0,0 +1,493 @@
+package services
+
+import (
+	config "backend/configs"
+	"backend/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	unfurlTimeout   = 5 * time.Second
+	unfurlMaxBody   = 1 << 20
+	unfurlErrorTTL  = time.Hour
+	unfurlUserAgent = "Mozilla/5.0 (compatible; BlogUnfurler/1.0)"
+	// Redirects followed before giving up.
+	unfurlMaxRedirects = 5
+)
+
+var (
+	ErrInvalidUnfurlURL = errors.New("only absolute http(s) URLs can be unfurled")
+	// ErrUnfurlBlocked is returned when a URL resolves to a loopback, private
+	// or otherwise internal address.
+	ErrUnfurlBlocked = errors.New("address is not allowed")
+	ErrUnfurlFailed  = errors.New("could not unfurl URL")
+)
+
+// UnfurlProvider describes a site whose links are unfurled through its
+// oEmbed endpoint rather than by scraping the page. Embed, when set, builds
+// the embed markup itself for sites without oEmbed.
+type UnfurlProvider struct {
+	Name   string
+	Hosts  []string
+	OEmbed string
+	Embed  func(link *url.URL) string
+}
+
+// DefaultUnfurlProviders are registered on every Unfurler.
+var DefaultUnfurlProviders = []UnfurlProvider{
+	{
+		Name:   "youtube",
+		Hosts:  []string{"youtube.com", "www.youtube.com", "m.youtube.com", "youtu.be"},
+		OEmbed: "https://www.youtube.com/oembed",
+	},
+	{
+		Name:   "vimeo",
+		Hosts:  []string{"vimeo.com", "www.vimeo.com", "player.vimeo.com"},
+		OEmbed: "https://vimeo.com/api/oembed.json",
+	},
+	{
+		Name:   "twitter",
+		Hosts:  []string{"twitter.com", "www.twitter.com", "mobile.twitter.com", "x.com", "www.x.com"},
+		OEmbed: "https://publish.twitter.com/oembed",
+	},
+	{
+		Name:  "gist",
+		Hosts: []string{"gist.github.com"},
+		Embed: func(link *url.URL) string {
+			parts := strings.Split(strings.Trim(link.Path, "/"), "/")
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return ""
+			}
+			src := fmt.Sprintf("https://gist.github.com/%s/%s.js", url.PathEscape(parts[0]), url.PathEscape(parts[1]))
+			return fmt.Sprintf(`<script src="%s"></script>`, html.EscapeString(src))
+		},
+	},
+}
+
+// Unfurler fetches link previews and caches them in the link_previews
+// table for ttl.
+type Unfurler struct {
+	client    *http.Client
+	ttl       time.Duration
+	mu        sync.RWMutex
+	providers map[string]*UnfurlProvider
+}
+
+var LinkUnfurler *Unfurler
+
+func NewUnfurler(client *http.Client, ttl time.Duration) *Unfurler {
+	unfurler := &Unfurler{client: client, ttl: ttl, providers: make(map[string]*UnfurlProvider)}
+	for _, provider := range DefaultUnfurlProviders {
+		unfurler.Register(provider)
+	}
+	return unfurler
+}
+
+// Register adds a provider, replacing any earlier one for the same hosts.
+func (u *Unfurler) Register(provider UnfurlProvider) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for _, host := range provider.Hosts {
+		registered := provider
+		u.providers[strings.ToLower(host)] = &registered
+	}
+}
+
+func (u *Unfurler) providerFor(host string) *UnfurlProvider {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+	return u.providers[strings.ToLower(host)]
+}
+
+// NewSafeHTTPClient returns a client that refuses to connect to internal
+// addresses. The check runs on the resolved IP of every connection,
+// redirects included, so DNS names pointing inside cannot slip through.
+func NewSafeHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !unfurlAllowedIP(ip) {
+				return ErrUnfurlBlocked
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:               nil, // a proxy would do the dialing for us
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        10,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= unfurlMaxRedirects {
+				return errTooManyRedirects
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return ErrInvalidUnfurlURL
+			}
+			return nil
+		},
+	}
+}
+
+var errTooManyRedirects = errors.New("too many redirects")
+
+// unfurlAllowedIP decides which addresses the unfurler may connect to. Tests
+// relax it to reach their local servers.
+var unfurlAllowedIP = isPublicIP
+
+// Special-purpose ranges the net.IP predicates don't cover.
+var internalNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // "this network"
+	"100.64.0.0/10", // carrier-grade NAT
+	"198.18.0.0/15", // benchmarking
+	"64:ff9b::/96",  // NAT64, which can reach any IPv4 address
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range internalNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeUnfurlURL validates the URL and drops the fragment, which never
+// changes what the server returns.
+func normalizeUnfurlURL(rawURL string) (*url.URL, error) {
+	link, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Hostname() == "" {
+		return nil, ErrInvalidUnfurlURL
+	}
+	link.Fragment = ""
+	link.Host = strings.ToLower(link.Host)
+	return link, nil
+}
+
+// Unfurl returns the preview for rawURL, from the cache when it is fresh.
+func (u *Unfurler) Unfurl(ctx context.Context, rawURL string) (models.LinkPreview, error) {
+	link, err := normalizeUnfurlURL(rawURL)
+	if err != nil {
+		return models.LinkPreview{}, err
+	}
+
+	sum := sha256.Sum256([]byte(link.String()))
+	hash := hex.EncodeToString(sum[:])
+
+	var cached models.LinkPreview
+	err = config.DB.Where("url_hash = ?", hash).First(&cached).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return cached, err
+	}
+	if err == nil {
+		ttl := u.ttl
+		if cached.Error != "" {
+			ttl = unfurlErrorTTL
+		}
+		if time.Since(cached.FetchedAt) < ttl {
+			if cached.Error != "" {
+				return cached, fmt.Errorf("%w: %s", ErrUnfurlFailed, cached.Error)
+			}
+			return cached, nil
+		}
+	}
+
+	preview, fetchErr := u.fetch(ctx, link)
+	if errors.Is(fetchErr, ErrUnfurlBlocked) || errors.Is(fetchErr, ErrInvalidUnfurlURL) {
+		return preview, fetchErr
+	}
+
+	preview.URLHash = hash
+	preview.URL = link.String()
+	preview.FetchedAt = time.Now()
+	if fetchErr != nil {
+		preview.Error = truncateRunes(fetchErr.Error(), 255)
+	}
+
+	if err := config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "url_hash"}},
+		UpdateAll: true,
+	}).Create(&preview).Error; err != nil {
+		return preview, err
+	}
+
+	if fetchErr != nil {
+		return preview, fmt.Errorf("%w: %v", ErrUnfurlFailed, fetchErr)
+	}
+	return preview, nil
+}
+
+func (u *Unfurler) fetch(ctx context.Context, link *url.URL) (models.LinkPreview, error) {
+	preview := models.LinkPreview{Type: "link"}
+
+	provider := u.providerFor(link.Hostname())
+	if provider != nil {
+		preview.Provider = provider.Name
+		if provider.OEmbed != "" {
+			endpoint, err := url.Parse(provider.OEmbed)
+			if err != nil {
+				return preview, err
+			}
+			query := endpoint.Query()
+			query.Set("url", link.String())
+			query.Set("format", "json")
+			endpoint.RawQuery = query.Encode()
+
+			if err := u.fetchOEmbed(ctx, endpoint.String(), &preview, true); err == nil {
+				return preview, nil
+			} else if errors.Is(err, ErrUnfurlBlocked) {
+				return preview, err
+			}
+			// Fall back to the page's own metadata
+		}
+	}
+
+	if err := u.fetchPage(ctx, link, &preview); err != nil {
+		return preview, err
+	}
+	if provider != nil && provider.Embed != nil {
+		preview.Type = "rich"
+		preview.HTML = provider.Embed(link)
+	}
+	return preview, nil
+}
+
+// get fetches a URL, reading at most unfurlMaxBody bytes.
+func (u *Unfurler) get(ctx context.Context, target, accept string) ([]byte, *url.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, unfurlTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("User-Agent", unfurlUserAgent)
+	req.Header.Set("Accept", accept)
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("%s returned %d", req.URL.Host, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, unfurlMaxBody))
+	return body, resp.Request.URL, err
+}
+
+type oEmbedResponse struct {
+	Type         string          `json:"type"`
+	Title        string          `json:"title"`
+	AuthorName   string          `json:"author_name"`
+	ProviderName string          `json:"provider_name"`
+	ThumbnailURL string          `json:"thumbnail_url"`
+	URL          string          `json:"url"` // photo type
+	HTML         string          `json:"html"`
+	Width        json.RawMessage `json:"width"`
+	Height       json.RawMessage `json:"height"`
+}
+
+// fetchOEmbed fills the preview from an oEmbed endpoint. The returned markup
+// is only kept from registered providers (trusted); discovered endpoints
+// contribute metadata only.
+func (u *Unfurler) fetchOEmbed(ctx context.Context, endpoint string, preview *models.LinkPreview, trusted bool) error {
+	body, _, err := u.get(ctx, endpoint, "application/json")
+	if err != nil {
+		return err
+	}
+
+	var response oEmbedResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return err
+	}
+
+	// Without the markup a discovered "rich" or "video" embed is just a link
+	if response.Type != "" && (trusted || response.Type == "photo") {
+		preview.Type = response.Type
+	}
+	if response.Title != "" {
+		preview.Title = response.Title
+	}
+	if response.AuthorName != "" {
+		preview.AuthorName = response.AuthorName
+	}
+	if response.ProviderName != "" {
+		preview.SiteName = response.ProviderName
+	}
+	if response.ThumbnailURL != "" {
+		preview.Image = response.ThumbnailURL
+	} else if response.Type == "photo" && response.URL != "" {
+		preview.Image = response.URL
+	}
+	// Some providers send dimensions as strings
+	fmt.Sscan(strings.Trim(string(response.Width), `"`), &preview.Width)
+	fmt.Sscan(strings.Trim(string(response.Height), `"`), &preview.Height)
+	if trusted {
+		preview.HTML = response.HTML
+	}
+	return nil
+}
+
+// fetchPage fills the preview from the page's Open Graph, Twitter card and
+// plain HTML metadata, following oEmbed discovery links when present.
+func (u *Unfurler) fetchPage(ctx context.Context, link *url.URL, preview *models.LinkPreview) error {
+	body, finalURL, err := u.get(ctx, link.String(), "text/html,application/xhtml+xml")
+	if err != nil {
+		return err
+	}
+
+	meta := make(map[string]string)
+	var title, oEmbedLink string
+	inTitle := false
+
+	tokenizer := html.NewTokenizer(strings.NewReader(string(body)))
+	for done := false; !done; {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			done = true
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			attrs := make(map[string]string, len(token.Attr))
+			for _, attr := range token.Attr {
+				attrs[strings.ToLower(attr.Key)] = attr.Val
+			}
+
+			switch token.Data {
+			case "title":
+				inTitle = title == ""
+			case "meta":
+				key := strings.ToLower(attrs["property"])
+				if key == "" {
+					key = strings.ToLower(attrs["name"])
+				}
+				if _, seen := meta[key]; key != "" && !seen {
+					meta[key] = strings.TrimSpace(attrs["content"])
+				}
+			case "link":
+				if strings.EqualFold(attrs["type"], "application/json+oembed") && oEmbedLink == "" {
+					oEmbedLink = attrs["href"]
+				}
+			case "body":
+				// Metadata lives in <head>
+				done = true
+			}
+		case html.TextToken:
+			if inTitle {
+				title = strings.TrimSpace(string(tokenizer.Text()))
+				inTitle = false
+			}
+		}
+	}
+
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if value := meta[key]; value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	preview.Title = first("og:title", "twitter:title")
+	if preview.Title == "" {
+		preview.Title = title
+	}
+	preview.Description = first("og:description", "twitter:description", "description")
+	preview.SiteName = first("og:site_name")
+	if preview.SiteName == "" {
+		preview.SiteName = finalURL.Hostname()
+	}
+	if image := first("og:image:secure_url", "og:image", "twitter:image", "twitter:image:src"); image != "" {
+		if resolved, err := finalURL.Parse(image); err == nil {
+			preview.Image = resolved.String()
+		}
+	}
+	if kind := first("og:type"); strings.HasPrefix(kind, "video") {
+		preview.Type = "video"
+	}
+
+	if oEmbedLink != "" && preview.Provider == "" {
+		if resolved, err := finalURL.Parse(oEmbedLink); err == nil {
+			if err := u.fetchOEmbed(ctx, resolved.String(), preview, false); err != nil && errors.Is(err, ErrUnfurlBlocked) {
+				return err
+			}
+		}
+	}
+
+	preview.Title = truncateRunes(preview.Title, 500)
+	preview.SiteName = truncateRunes(preview.SiteName, 200)
+	preview.AuthorName = truncateRunes(preview.AuthorName, 200)
+	return nil
+}
+
+// SetupUnfurler creates LinkUnfurler. UNFURL_CACHE_TTL (default 24h) is how
+// long a preview is reused before the link is fetched again.
+func SetupUnfurler() error {
+	ttl := 24 * time.Hour
+	if value := os.Getenv("UNFURL_CACHE_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid UNFURL_CACHE_TTL: %w", err)
+		}
+		ttl = parsed
+	}
+
+	LinkUnfurler = NewUnfurler(NewSafeHTTPClient(unfurlTimeout), ttl)
+	return nil
+}
+
+// truncateRunes cuts value to limit characters, the unit varchar sizes are
+// measured in.
+func truncateRunes(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit])
+}
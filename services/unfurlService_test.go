@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// allowLoopback lets the safe client reach httptest servers while keeping
+// every other check.
+func allowLoopback(t *testing.T) {
+	previous := unfurlAllowedIP
+	unfurlAllowedIP = func(ip net.IP) bool { return ip.IsLoopback() || isPublicIP(ip) }
+	t.Cleanup(func() { unfurlAllowedIP = previous })
+}
+
+func serve(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	link, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return link
+}
+
+func TestFetchPageReadsOpenGraph(t *testing.T) {
+	server := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head>
+			<title>Plain title</title>
+			<meta property="og:title" content="OG title">
+			<meta name="description" content="Plain description">
+			<meta property="og:description" content="OG description">
+			<meta property="og:site_name" content="Example">
+			<meta property="og:image" content="/cover.png">
+			<meta property="og:type" content="video.other">
+		</head><body><meta property="og:title" content="Ignored"></body></html>`)
+	})
+
+	preview, err := NewUnfurler(server.Client(), time.Hour).fetch(context.Background(), mustParseURL(t, server.URL+"/article"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if preview.Title != "OG title" || preview.Description != "OG description" || preview.SiteName != "Example" {
+		t.Errorf("got title %q, description %q, site %q", preview.Title, preview.Description, preview.SiteName)
+	}
+	if preview.Image != server.URL+"/cover.png" {
+		t.Errorf("image = %q, want it resolved against the page", preview.Image)
+	}
+	if preview.Type != "video" {
+		t.Errorf("type = %q, want video", preview.Type)
+	}
+}
+
+func TestFetchPageFallsBackToTitleTag(t *testing.T) {
+	server := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title> Plain title </title></head><body></body></html>`)
+	})
+
+	preview, err := NewUnfurler(server.Client(), time.Hour).fetch(context.Background(), mustParseURL(t, server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Title != "Plain title" || preview.Type != "link" {
+		t.Errorf("got title %q, type %q", preview.Title, preview.Type)
+	}
+	if host := mustParseURL(t, server.URL).Hostname(); preview.SiteName != host {
+		t.Errorf("site = %q, want the host %q", preview.SiteName, host)
+	}
+}
+
+func TestFetchUsesProviderOEmbed(t *testing.T) {
+	var query url.Values
+	server := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `{"type":"video","title":"Clip","author_name":"Someone","provider_name":"Tube",
+			"thumbnail_url":"https://img.example/t.jpg","html":"<iframe></iframe>","width":"640","height":360}`)
+	})
+
+	unfurler := NewUnfurler(server.Client(), time.Hour)
+	unfurler.Register(UnfurlProvider{Name: "tube", Hosts: []string{"video.example"}, OEmbed: server.URL + "/oembed"})
+
+	preview, err := unfurler.fetch(context.Background(), mustParseURL(t, "https://video.example/watch?v=1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if query.Get("url") != "https://video.example/watch?v=1" || query.Get("format") != "json" {
+		t.Errorf("oEmbed query = %v", query)
+	}
+	if preview.Provider != "tube" || preview.Type != "video" || preview.Title != "Clip" ||
+		preview.AuthorName != "Someone" || preview.SiteName != "Tube" || preview.Image != "https://img.example/t.jpg" {
+		t.Errorf("unexpected preview %+v", preview)
+	}
+	if preview.Width != 640 || preview.Height != 360 {
+		t.Errorf("size = %dx%d, want 640x360", preview.Width, preview.Height)
+	}
+	if preview.HTML != "<iframe></iframe>" {
+		t.Errorf("html = %q, want the provider's markup", preview.HTML)
+	}
+}
+
+func TestDiscoveredOEmbedKeepsNoMarkup(t *testing.T) {
+	server := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oembed" {
+			fmt.Fprint(w, `{"type":"rich","title":"From oEmbed","html":"<script>alert(1)</script>"}`)
+			return
+		}
+		fmt.Fprint(w, `<html><head><title>Page</title>
+			<link rel="alternate" type="application/json+oembed" href="/oembed?url=x">
+		</head></html>`)
+	})
+
+	preview, err := NewUnfurler(server.Client(), time.Hour).fetch(context.Background(), mustParseURL(t, server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Title != "From oEmbed" {
+		t.Errorf("title = %q, want the discovered oEmbed title", preview.Title)
+	}
+	if preview.HTML != "" || preview.Type != "link" {
+		t.Errorf("discovered endpoint set type %q and html %q", preview.Type, preview.HTML)
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for address, want := range map[string]bool{
+		"93.184.216.34":    true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"fe80::1":          false,
+		"fd00::1":          false,
+		"0.0.0.0":          false,
+		"0.1.2.3":          false,
+		"100.64.0.1":       false,
+		"198.18.0.1":       false,
+		"198.19.255.255":   false,
+		"64:ff9b::a00:1":   false,
+		"::ffff:127.0.0.1": false,
+		"224.0.0.1":        false,
+	} {
+		if got := isPublicIP(net.ParseIP(address)); got != want {
+			t.Errorf("isPublicIP(%s) = %v, want %v", address, got, want)
+		}
+	}
+}
+
+func TestSafeClientBlocksInternalTargets(t *testing.T) {
+	server := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "reachable")
+	})
+	client := NewSafeHTTPClient(time.Second)
+
+	// Dialing is refused before any packet is sent, so unroutable targets
+	// fail just as fast as the local server.
+	for _, target := range []string{
+		server.URL,
+		"http://10.0.0.1/",
+		"http://192.168.0.1/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]/",
+	} {
+		resp, err := client.Get(target)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if !errors.Is(err, ErrUnfurlBlocked) {
+			t.Errorf("GET %s: err = %v, want ErrUnfurlBlocked", target, err)
+		}
+	}
+}
+
+func TestSafeClientStopsAfterTooManyRedirects(t *testing.T) {
+	allowLoopback(t)
+
+	hops := 0
+	server := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		hops++
+		http.Redirect(w, r, fmt.Sprintf("/hop/%d", hops), http.StatusFound)
+	})
+
+	resp, err := NewSafeHTTPClient(time.Second).Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+	}
+	if !errors.Is(err, errTooManyRedirects) {
+		t.Fatalf("err = %v, want errTooManyRedirects", err)
+	}
+	if hops != unfurlMaxRedirects {
+		t.Errorf("followed %d requests, want %d", hops, unfurlMaxRedirects)
+	}
+}
+
+func TestSafeClientBlocksRedirectToPrivateAddress(t *testing.T) {
+	allowLoopback(t)
+
+	server := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://10.0.0.1/admin", http.StatusFound)
+	})
+
+	resp, err := NewSafeHTTPClient(time.Second).Get(server.URL)
+	if err == nil {
+		resp.Body.Close()
+	}
+	if !errors.Is(err, ErrUnfurlBlocked) {
+		t.Fatalf("err = %v, want ErrUnfurlBlocked", err)
+	}
+}